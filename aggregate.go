@@ -0,0 +1,190 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type (
+	// SheetError DecodeWorkbook 中一个sheet的错误，Sheet 为sheet名称(无法选择sheet时为标签中的值)，Field 为该sheet对应的属性名。
+	// 行转换失败时 Err 为 RowError
+	SheetError struct {
+		Sheet string
+		Field string
+		Err   error
+	}
+
+	// sheetField 聚合结构体中一个带有 eorm_sheet 标签的属性
+	sheetField struct {
+		index    int
+		name     string
+		selector string       // 标签中的sheet名称，或者"#下标"
+		optional bool         // sheet不存在时不报错
+		elemType reflect.Type // 行对象类型(结构体)
+		pointer  bool         // slice元素为指针
+	}
+)
+
+const (
+	SheetTagName     = "eorm_sheet"
+	sheetTagOptional = ",optional"
+)
+
+func (e SheetError) Error() string {
+	return fmt.Sprintf("eorm: sheet %s (field %s): %v", e.Sheet, e.Field, e.Err)
+}
+
+func (e SheetError) Unwrap() error { return e.Err }
+
+// DecodeWorkbook 将wb中的多个sheet转换到dst指向的结构体中，dst的每个带有 eorm_sheet 标签的属性对应一个sheet，
+// 属性类型为 []T 或 []*T，T为使用eorm标签的结构体。标签的值为sheet名称，"#n"表示下标为n的sheet，
+// 以",optional"结尾时sheet不存在不报错。例如：
+//
+//	type Report struct {
+//		Orders    []Order     `eorm_sheet:"Orders"`
+//		Customers []*Customer `eorm_sheet:"#1"`
+//		Products  []Product   `eorm_sheet:"Products,optional"`
+//	}
+//
+// 所有sheet使用opts，WithSheetOptions 为某个sheet追加参数(以标签中","之前的部分为key)。
+// sheet或者行转换出错时不会停止，转换成功的行仍然保存到属性中，所有的错误以 SheetError 的形式通过 errors.Join 返回。
+// dst的类型不符合要求时直接返回错误，不修改dst。
+// 每个sheet的遍历规则与 EORM.Next 相同，行对象的类型注册了 RowDecoder 时使用decoder转换，结果与 EORM 一致
+func DecodeWorkbook(wb Workbook, dst any, opts ...Option) error {
+	return DecodeWorkbookContext(context.Background(), wb, dst, opts...)
+}
+
+// DecodeWorkbookContext 与 DecodeWorkbook 相同，ctx被取消后停止转换，已转换的行仍然保存到属性中，
+// 返回的错误中包含 ctx.Err()。wb实现了 ContextWorkbook 时，ctx同时传递给读取sheet及行的过程
+func DecodeWorkbookContext(ctx context.Context, wb Workbook, dst any, opts ...Option) error {
+	if wb == nil || dst == nil {
+		return ErrNil
+	}
+	val := reflect.ValueOf(dst)
+	if val.Kind() != reflect.Pointer || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("eorm: dst must be a non-nil pointer to struct, got %T", dst)
+	}
+	fields, err := parseSheetFields(val.Elem().Type())
+	if err != nil {
+		return err
+	}
+	params := NewParams(opts...)
+	var errs []error
+	for _, sf := range fields {
+		if err = ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		rows, sheetErrs := sf.decode(ctx, wb, params)
+		if rows.IsValid() {
+			val.Elem().Field(sf.index).Set(rows)
+		}
+		errs = append(errs, sheetErrs...)
+	}
+	return errors.Join(errs...)
+}
+
+func parseSheetFields(typ reflect.Type) ([]*sheetField, error) {
+	var fields []*sheetField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup(SheetTagName)
+		if !ok {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("eorm: field %s with %s tag must be exported", field.Name, SheetTagName)
+		}
+		sf := &sheetField{index: i, name: field.Name}
+		sf.selector, sf.optional = strings.CutSuffix(tag, sheetTagOptional)
+		if sf.selector == "" {
+			return nil, fmt.Errorf("eorm: empty %s tag of field %s", SheetTagName, field.Name)
+		}
+		if field.Type.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("eorm: field %s with %s tag must be a slice, got %s", field.Name, SheetTagName, field.Type)
+		}
+		sf.elemType = field.Type.Elem()
+		if sf.elemType.Kind() == reflect.Pointer {
+			sf.elemType, sf.pointer = sf.elemType.Elem(), true
+		}
+		if sf.elemType.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("eorm: field %s with %s tag must be a slice of struct, got %s", field.Name, SheetTagName, field.Type)
+		}
+		fields = append(fields, sf)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("eorm: no %s tag found in %s", SheetTagName, typ)
+	}
+	return fields, nil
+}
+
+// selectSheet 返回sheet，sheet不存在且optional时返回nil, nil
+func (sf *sheetField) selectSheet(ctx context.Context, wb Workbook) (Sheet, error) {
+	var sheet Sheet
+	var err error
+	if s, ok := strings.CutPrefix(sf.selector, "#"); ok {
+		index, perr := strconv.Atoi(s)
+		if perr != nil {
+			return nil, fmt.Errorf("eorm: invalid sheet index %q", sf.selector)
+		}
+		sheet, err = GetSheetContext(ctx, wb, index)
+	} else {
+		sheet, err = GetSheetByNameContext(ctx, wb, sf.selector)
+	}
+	if err != nil && sf.optional && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrOutOfRange)) {
+		return nil, nil
+	}
+	return sheet, err
+}
+
+// decode 转换sf对应的sheet，返回 field 类型的slice(sheet不存在时无效)和所有错误。
+// 与 EORM 使用同样的 rowCursor 遍历，超出 Params.Limits、读取行出错(未设置 IgnoreReadRowError)或者ctx被取消时停止
+func (sf *sheetField) decode(ctx context.Context, wb Workbook, params *Params) (reflect.Value, []error) {
+	sheetName := sf.selector
+	sheetErr := func(err error) error { return SheetError{Sheet: sheetName, Field: sf.name, Err: err} }
+	sheet, err := sf.selectSheet(ctx, wb)
+	if err != nil {
+		return reflect.Value{}, []error{sheetErr(fmt.Errorf("select sheet: %w", err))}
+	}
+	if sheet == nil {
+		return reflect.Value{}, nil
+	}
+	sheetName = sheet.GetName()
+	if opts := params.SheetOptions[sf.selector]; len(opts) > 0 {
+		params = NewParams(append([]Option{WithParams(params)}, opts...)...)
+	}
+	schema, err := CompileMapperSchema(sf.elemType)
+	if err != nil {
+		return reflect.Value{}, []error{sheetErr(err)}
+	}
+	binding, tree, err := bindSchema(schema, sheet, params)
+	if err != nil {
+		return reflect.Value{}, []error{sheetErr(err)}
+	}
+	sliceType := sf.elemType
+	if sf.pointer {
+		sliceType = reflect.PointerTo(sliceType)
+	}
+	cursor := newRowCursor(sheet, params, params.MinRows(tree.Depth()))
+	rows := reflect.MakeSlice(reflect.SliceOf(sliceType), 0, max(sheet.RowCount()-cursor.startRow, 0))
+	var errs []error
+	for cursor.next(ctx) {
+		obj, err := binding.decodeValue(cursor.currentRow)
+		if err != nil {
+			errs = append(errs, sheetErr(RowError{RowIndex: cursor.rowIndex, Err: err}))
+			continue
+		}
+		if !sf.pointer {
+			obj = obj.Elem()
+		}
+		rows = reflect.Append(rows, obj)
+	}
+	if err = cursor.stopError(); err != nil {
+		errs = append(errs, sheetErr(err))
+	}
+	return rows, errs
+}
@@ -0,0 +1,184 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+type (
+	aggOrder struct {
+		ID     int64  `eorm:"订单号"`
+		Amount int64  `eorm:"金额"`
+		Buyer  string `eorm:"客户"`
+	}
+
+	aggCustomer struct {
+		Name string `eorm:"客户信息/名称"`
+		City string `eorm:"客户信息/城市"`
+	}
+
+	aggReport struct {
+		Orders    []aggOrder     `eorm_sheet:"Orders"`
+		Customers []*aggCustomer `eorm_sheet:"#1"`
+		Products  []aggOrder     `eorm_sheet:"Products,optional"`
+		Title     string
+	}
+)
+
+// aggOrderDecoder 手写的 aggOrder 的 RowDecoder，记录调用次数，用于确认 DecodeWorkbook 使用了注册的decoder
+type aggOrderDecoder struct {
+	fingerprint string
+	calls       *atomic.Int32
+}
+
+func (d aggOrderDecoder) DecodeRow(row Row, columns [][]int, params *Params) (*aggOrder, error) {
+	d.calls.Add(1)
+	obj := new(aggOrder)
+	for fieldIndex, cols := range columns {
+		if len(cols) == 0 {
+			continue
+		}
+		var err error
+		switch fieldIndex {
+		case 0:
+			obj.ID, err = ColumnInt64(row, cols[0], "", params)
+		case 1:
+			obj.Amount, err = ColumnInt64(row, cols[0], "", params)
+		case 2:
+			obj.Buyer, err = ColumnString(row, cols[0], "", params)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+func (d aggOrderDecoder) Fingerprint() string { return d.fingerprint }
+
+// newAggWorkbook Orders的第3行金额无法转换，第二个sheet的表头从第2行开始
+func newAggWorkbook(t *testing.T) Workbook {
+	return newSheetsWorkbook(t, []string{"Orders", "客户"}, map[string][][]any{
+		"Orders": {
+			{"订单号", "金额", "客户"},
+			{1, 100, "张三"},
+			{2, "abc", "李四"},
+			{3, 300, "王五"},
+		},
+		"客户": {
+			{"月度客户"},
+			{"客户信息", nil},
+			{"名称", "城市"},
+			{"张三", "北京"},
+			{"李四", "上海"},
+		},
+	})
+}
+
+func TestDecodeWorkbook(t *testing.T) {
+	wb := newAggWorkbook(t)
+	defer func() {
+		_ = wb.Close()
+	}()
+
+	var report aggReport
+	err := DecodeWorkbook(wb, &report, WithSheetOptions("#1", WithTitleStartRow(1)))
+	var rowErr RowError
+	if !errors.As(err, &rowErr) || rowErr.RowIndex != 2 {
+		t.Fatalf("want RowError at row 2, got %v", err)
+	}
+	var sheetErr SheetError
+	if !errors.As(err, &sheetErr) || sheetErr.Field != "Orders" {
+		t.Fatalf("want SheetError of Orders, got %v", err)
+	}
+	wantOrders := []aggOrder{{ID: 1, Amount: 100, Buyer: "张三"}, {ID: 3, Amount: 300, Buyer: "王五"}}
+	if !reflect.DeepEqual(report.Orders, wantOrders) {
+		t.Fatalf("orders: got %+v", report.Orders)
+	}
+	if len(report.Customers) != 2 || *report.Customers[1] != (aggCustomer{Name: "李四", City: "上海"}) {
+		t.Fatalf("customers: got %+v", report.Customers)
+	}
+	if report.Products != nil {
+		t.Fatalf("optional sheet not found, products should be nil: %+v", report.Products)
+	}
+
+	// 缺少per-sheet参数时第二个sheet的表头无法匹配，但不影响其他sheet
+	report = aggReport{}
+	err = DecodeWorkbook(wb, &report, WithIgnoreParseError(), WithMatchLevel(MatchLevelMatched))
+	if !errors.As(err, &sheetErr) || sheetErr.Field != "Customers" || sheetErr.Sheet != "客户" ||
+		!errors.Is(err, ErrInsufficientMatchLevel) {
+		t.Fatalf("want SheetError of Customers, got %v", err)
+	}
+	if len(report.Orders) != 3 {
+		t.Fatalf("orders: got %+v", report.Orders)
+	}
+
+	var missing struct {
+		Lines []aggOrder `eorm_sheet:"Lines"`
+	}
+	if err = DecodeWorkbook(wb, &missing); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+	for _, dst := range []any{report, &struct{ Orders []aggOrder }{}, &struct {
+		Orders aggOrder `eorm_sheet:"Orders"`
+	}{}} {
+		if err = DecodeWorkbook(wb, dst); err == nil {
+			t.Fatalf("invalid dst %T should fail", dst)
+		}
+	}
+}
+
+func TestDecodeWorkbookDecoder(t *testing.T) {
+	wb := newAggWorkbook(t)
+	defer func() {
+		_ = wb.Close()
+	}()
+	var want struct {
+		Orders []aggOrder `eorm_sheet:"Orders"`
+	}
+	wantErr := DecodeWorkbook(wb, &want)
+
+	schema, err := CompileMapperSchema(reflect.TypeFor[aggOrder]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoder := aggOrderDecoder{fingerprint: schema.Fingerprint(), calls: new(atomic.Int32)}
+	if err = RegisterDecoder[aggOrder](decoder); err != nil {
+		t.Fatal(err)
+	}
+	defer rowDecoders.Delete(reflect.TypeFor[aggOrder]())
+
+	got := want
+	got.Orders = nil
+	err = DecodeWorkbook(wb, &got)
+	if decoder.calls.Load() != 3 {
+		t.Fatalf("registered decoder should convert 3 rows, got %d", decoder.calls.Load())
+	}
+	if !reflect.DeepEqual(got, want) || fmt.Sprint(err) != fmt.Sprint(wantErr) {
+		t.Fatalf("decoder: %+v %v, reflection: %+v %v", got, err, want, wantErr)
+	}
+	decoder.calls.Store(0)
+	if err = DecodeWorkbook(wb, &got, WithReflection()); decoder.calls.Load() != 0 {
+		t.Fatalf("WithReflection should not use the registered decoder: %v", err)
+	}
+}
+
+func TestDecodeWorkbookContext(t *testing.T) {
+	wb := newAggWorkbook(t)
+	defer func() {
+		_ = wb.Close()
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var report aggReport
+	if err := DecodeWorkbookContext(ctx, wb, &report); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+	if report.Orders != nil || report.Customers != nil {
+		t.Fatalf("nothing should be decoded after cancel: %+v", report)
+	}
+}
@@ -0,0 +1,70 @@
+package eorm
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+type (
+	// RowError 转换失败的行及其错误，RowIndex 为该行在sheet中的行下标
+	RowError struct {
+		RowIndex int
+		Err      error
+	}
+
+	// Batch 一批转换成功的对象，Objs[i] 由sheet中的第 RowIndexes[i] 行转换而来。
+	// Errors 为同一批次的行中转换失败的行，这些行不计入批次的大小
+	Batch[T any] struct {
+		Objs       []*T
+		RowIndexes []int
+		Errors     []RowError
+	}
+)
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("eorm: row %d: %v", e.RowIndex, e.Err)
+}
+
+func (e RowError) Unwrap() error { return e.Err }
+
+func (b *Batch[T]) Len() int { return len(b.Objs) }
+
+func (b *Batch[T]) isEmpty() bool { return len(b.Objs) == 0 && len(b.Errors) == 0 }
+
+// Batches 由当前的遍历位置开始，每n个转换成功的对象组成一个批次返回，n<=0时所有的对象组成一个批次。
+// 最后一个批次的对象数可能小于n；遍历提前结束(ctx被取消、超出 Params.Limits 或者读取行失败)时，
+// 最后一次返回已读取的批次(可能为nil)及导致结束的错误
+func (e *EORM[T]) Batches(n int) iter.Seq2[*Batch[T], error] {
+	return e.BatchesContext(context.Background(), n)
+}
+
+// BatchesContext 与 Batches() 相同，ctx被取消后停止遍历
+func (e *EORM[T]) BatchesContext(ctx context.Context, n int) iter.Seq2[*Batch[T], error] {
+	return func(yield func(*Batch[T], error) bool) {
+		batch := new(Batch[T])
+		for e.NextContext(ctx) {
+			t, err := e.Current()
+			if err != nil {
+				batch.Errors = append(batch.Errors, RowError{RowIndex: e.rowIndex, Err: err})
+				continue
+			}
+			batch.Objs = append(batch.Objs, t)
+			batch.RowIndexes = append(batch.RowIndexes, e.rowIndex)
+			if n > 0 && len(batch.Objs) >= n {
+				if !yield(batch, nil) {
+					return
+				}
+				batch = new(Batch[T])
+			}
+		}
+		err := e.stopError()
+		if batch.isEmpty() {
+			if err != nil {
+				yield(nil, err)
+			}
+			return
+		}
+		yield(batch, err)
+	}
+}
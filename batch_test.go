@@ -0,0 +1,60 @@
+package eorm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBatches(t *testing.T) {
+	const rows = 1000
+	sheet := parallelSheet(t, rows)
+	em, err := NewEORM[parallelObj](sheet, reflect.TypeOf(parallelObj{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	objs, errs, batches := 0, 0, 0
+	for batch, err := range em.Batches(400) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		batches++
+		if batches < 3 && batch.Len() != 400 {
+			t.Fatalf("batch %d: expecting 400 objects, got %d", batches, batch.Len())
+		}
+		for i, obj := range batch.Objs {
+			// 数据从sheet的第1行开始，序号从0开始
+			if int(obj.Id) != batch.RowIndexes[i]-1 {
+				t.Fatalf("row %d: unexpected %+v", batch.RowIndexes[i], obj)
+			}
+		}
+		for _, rowErr := range batch.Errors {
+			if (rowErr.RowIndex-1)%10 != 3 || !errors.Is(rowErr, ErrParseError) {
+				t.Fatalf("unexpected row error %v", rowErr)
+			}
+		}
+		objs += batch.Len()
+		errs += len(batch.Errors)
+	}
+	if batches != 3 || objs != rows-rows/10 || errs != rows/10 {
+		t.Fatalf("expecting 3 batches %d objects %d errors, got %d batches %d objects %d errors",
+			rows-rows/10, rows/10, batches, objs, errs)
+	}
+
+	// 超出限制时，最后返回已读取的批次及错误
+	em, err = NewEORM[parallelObj](sheet, reflect.TypeOf(parallelObj{}), WithLimits(Limits{MaxRows: 501}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	objs = 0
+	var lastErr error
+	for batch, err := range em.Batches(100) {
+		if batch != nil {
+			objs += batch.Len() + len(batch.Errors)
+		}
+		lastErr = err
+	}
+	if objs != 500 || !errors.Is(lastErr, ErrTooManyRows) {
+		t.Fatalf("expecting 500 rows and ErrTooManyRows, got %d rows and %v", objs, lastErr)
+	}
+}
@@ -0,0 +1,467 @@
+package eorm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+	"unicode/utf16"
+)
+
+// BIFF8 的限制
+const (
+	XlsMaxRows        = 65536
+	XlsMaxColumns     = 256
+	XlsMaxStringChars = 32767
+	xlsMaxSheetName   = 31
+	xlsMaxRecordData  = 8224 // 记录数据的最大长度，超出的部分使用 CONTINUE 记录
+	xlsMaxMergedCells = 1026 // 一个 MERGEDCELLS 记录中区域的最大数量
+)
+
+// BIFF8 记录类型
+const (
+	biffBOF         = 0x0809
+	biffEOF         = 0x000A
+	biffCodepage    = 0x0042
+	biffWindow1     = 0x003D
+	biffDateMode    = 0x0022
+	biffFont        = 0x0031
+	biffXF          = 0x00E0
+	biffStyle       = 0x0293
+	biffBoundSheet  = 0x0085
+	biffSST         = 0x00FC
+	biffExtSST      = 0x00FF
+	biffContinue    = 0x003C
+	biffDimensions  = 0x0200
+	biffWindow2     = 0x023E
+	biffLabelSST    = 0x00FD
+	biffNumber      = 0x0203
+	biffBoolErr     = 0x0205
+	biffMergedCells = 0x00E5
+)
+
+// XF 下标：0-14 为样式XF，15 为单元格的缺省XF，16/17 为日期/日期时间
+const (
+	xlsXFGeneral  = 15
+	xlsXFDate     = 16
+	xlsXFDateTime = 17
+)
+
+var ErrXlsLimitExceeded = errors.New("excel/xls: limit exceeded")
+
+type (
+	// xlsWriter 生成只有一个sheet的BIFF8格式xls，所有单元格保存在内存中，SaveTo 时一次性生成文件
+	xlsWriter struct {
+		name   string
+		cells  map[xlsCellKey]any // 值为 string、float64、bool 或 time.Time
+		merged []CellRange
+	}
+
+	xlsCellKey struct {
+		row, column int
+	}
+
+	// biffStream workbook流，记录的位置即其在流中的偏移
+	biffStream struct {
+		bytes.Buffer
+	}
+)
+
+func newXlsWriter(sheetName string) (*xlsWriter, error) {
+	if len(utf16.Encode([]rune(sheetName))) > xlsMaxSheetName {
+		return nil, fmt.Errorf("%w: sheet name longer than %d characters", ErrXlsLimitExceeded, xlsMaxSheetName)
+	}
+	return &xlsWriter{name: sheetName, cells: make(map[xlsCellKey]any)}, nil
+}
+
+func (x *xlsWriter) SetCell(rowIndex, columnIndex int, value any) error {
+	if rowIndex < 0 || rowIndex >= XlsMaxRows || columnIndex < 0 || columnIndex >= XlsMaxColumns {
+		return fmt.Errorf("%w: cell (%d, %d)", ErrXlsLimitExceeded, rowIndex, columnIndex)
+	}
+	key := xlsCellKey{row: rowIndex, column: columnIndex}
+	v, err := xlsCellValue(value)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		delete(x.cells, key)
+	} else {
+		x.cells[key] = v
+	}
+	return nil
+}
+
+// xlsCellValue 将value转换为 string、float64、bool 或 time.Time，nil及空字符串返回nil
+func xlsCellValue(value any) (any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		if len(utf16.Encode([]rune(v))) > XlsMaxStringChars {
+			return nil, fmt.Errorf("%w: string longer than %d characters", ErrXlsLimitExceeded, XlsMaxStringChars)
+		}
+		return v, nil
+	case bool, time.Time:
+		return v, nil
+	case *time.Time:
+		if v == nil {
+			return nil, nil
+		}
+		return *v, nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		return xlsCellValue(rv.String())
+	default:
+		return xlsCellValue(fmt.Sprint(value))
+	}
+}
+
+func (x *xlsWriter) MergeCells(area CellRange) error {
+	if area.FirstRow < 0 || area.FirstColumn < 0 || area.LastRow < area.FirstRow || area.LastColumn < area.FirstColumn ||
+		area.LastRow >= XlsMaxRows || area.LastColumn >= XlsMaxColumns {
+		return fmt.Errorf("%w: merged cells %s", ErrXlsLimitExceeded, area)
+	}
+	x.merged = append(x.merged, area)
+	return nil
+}
+
+func (x *xlsWriter) Close() error { return nil }
+
+func (x *xlsWriter) SaveTo(w io.Writer) error {
+	if err := writeCompoundFile(w, "Workbook", x.workbookStream()); err != nil {
+		return fmt.Errorf("excel/xls: %w", err)
+	}
+	return nil
+}
+
+// workbookStream 生成workbook流：全局子流(字体、XF、sheet、共享字符串表)之后是sheet子流
+func (x *xlsWriter) workbookStream() []byte {
+	keys := slices.SortedFunc(maps.Keys(x.cells), func(a, b xlsCellKey) int {
+		if a.row != b.row {
+			return a.row - b.row
+		}
+		return a.column - b.column
+	})
+	var sst []string
+	sstIndex := make(map[string]int)
+	labels := 0
+	for _, k := range keys {
+		if s, ok := x.cells[k].(string); ok {
+			labels++
+			if _, exists := sstIndex[s]; !exists {
+				sstIndex[s] = len(sst)
+				sst = append(sst, s)
+			}
+		}
+	}
+
+	var globals biffStream
+	globals.bof(0x0005)
+	globals.record(biffCodepage, le16(1200))
+	globals.record(biffWindow1, le16(0, 0, 0x4000, 0x2000, 0x0038, 0, 0, 1, 0x0258))
+	globals.record(biffDateMode, le16(0))
+	for i := 0; i < 4; i++ {
+		globals.font("Arial")
+	}
+	for i := 0; i < 15; i++ {
+		globals.xf(0, true)
+	}
+	globals.xf(0, false)
+	globals.xf(14, false)
+	globals.xf(22, false)
+	globals.record(biffStyle, append(le16(0x8000), 0x00, 0xFF))
+	// BOUNDSHEET 中sheet子流的位置在全局子流结束后才能确定
+	boundSheet := globals.Len() + 4
+	name := shortUnicodeString(x.name)
+	globals.record(biffBoundSheet, append(append(le32(0), 0, 0), name...))
+	globals.sst(sst, labels)
+	globals.record(biffEOF, nil)
+
+	stream := globals.Bytes()
+	binary.LittleEndian.PutUint32(stream[boundSheet:], uint32(len(stream)))
+
+	var sheet biffStream
+	sheet.bof(0x0010)
+	var rows, columns int
+	for _, k := range keys {
+		rows, columns = max(rows, k.row+1), max(columns, k.column+1)
+	}
+	for _, m := range x.merged {
+		rows, columns = max(rows, m.LastRow+1), max(columns, m.LastColumn+1)
+	}
+	sheet.record(biffDimensions, append(le32(0, uint32(rows)), le16(0, uint16(columns), 0)...))
+	sheet.record(biffWindow2, append(le16(0x06B6, 0, 0, 64, 0, 0, 0), le32(0)...))
+	for _, k := range keys {
+		cell := le16(uint16(k.row), uint16(k.column), xlsXFGeneral)
+		switch v := x.cells[k].(type) {
+		case string:
+			sheet.record(biffLabelSST, append(cell, le32(uint32(sstIndex[v]))...))
+		case float64:
+			sheet.record(biffNumber, append(cell, le64(math.Float64bits(v))...))
+		case bool:
+			b := byte(0)
+			if v {
+				b = 1
+			}
+			sheet.record(biffBoolErr, append(cell, b, 0))
+		case time.Time:
+			serial, xf := xlsDateSerial(v)
+			cell = le16(uint16(k.row), uint16(k.column), xf)
+			sheet.record(biffNumber, append(cell, le64(math.Float64bits(serial))...))
+		}
+	}
+	for merged := x.merged; len(merged) > 0; {
+		n := min(len(merged), xlsMaxMergedCells)
+		data := le16(uint16(n))
+		for _, m := range merged[:n] {
+			data = append(data, le16(uint16(m.FirstRow), uint16(m.LastRow), uint16(m.FirstColumn), uint16(m.LastColumn))...)
+		}
+		sheet.record(biffMergedCells, data)
+		merged = merged[n:]
+	}
+	sheet.record(biffEOF, nil)
+	return append(stream, sheet.Bytes()...)
+}
+
+// xlsDateSerial 返回1900日期系统中的序列值，没有时间部分时使用日期格式，否则使用日期时间格式
+func xlsDateSerial(t time.Time) (float64, uint16) {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	serial := wall.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)).Hours() / 24
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return serial, xlsXFDate
+	}
+	return serial, xlsXFDateTime
+}
+
+func (b *biffStream) record(typ uint16, data []byte) {
+	b.Write(le16(typ, uint16(len(data))))
+	b.Write(data)
+}
+
+func (b *biffStream) bof(typ uint16) {
+	b.record(biffBOF, append(le16(0x0600, typ, 0x0DBB, 0x07CC), le32(0, 0x0006)...))
+}
+
+// font 10号字体
+func (b *biffStream) font(name string) {
+	data := le16(200, 0, 0x7FFF, 400, 0)
+	data = append(data, 0, 0, 0, 0)
+	b.record(biffFont, append(data, shortUnicodeString(name)...))
+}
+
+// xf 使用第一个字体的XF，style 为true时为样式XF，否则为父样式为0的单元格XF
+func (b *biffStream) xf(format uint16, style bool) {
+	protection, used := uint16(0x0001), byte(0x00)
+	if style {
+		protection, used = 0xFFF5, 0xF4
+	} else if format != 0 {
+		used = 0x04
+	}
+	data := le16(0, format, protection)
+	data = append(data, 0x20, 0, 0, used)
+	data = append(data, le32(0, 0)...)
+	b.record(biffXF, append(data, le16(0x20C0)...))
+}
+
+// sst 写入共享字符串表及其索引 EXTSST，字符串均使用UTF-16LE，超过记录长度的部分写入 CONTINUE 记录
+func (b *biffStream) sst(sst []string, total int) {
+	dsst := max(8, (len(sst)+127)/128)
+	var buckets []byte
+	typ, rec := uint16(biffSST), le32(uint32(total), uint32(len(sst)))
+	flush := func() {
+		b.record(typ, rec)
+		typ, rec = biffContinue, nil
+	}
+	for i, s := range sst {
+		units := utf16.Encode([]rune(s))
+		// 字符串的长度和标志位不能被拆分，且至少包含一个字符
+		if xlsMaxRecordData-len(rec) < 5 {
+			flush()
+		}
+		if i%dsst == 0 {
+			offset := 4 + len(rec)
+			buckets = append(buckets, le32(uint32(b.Len()+offset))...)
+			buckets = append(buckets, le16(uint16(offset), 0)...)
+		}
+		rec = append(rec, le16(uint16(len(units)))...)
+		rec = append(rec, 0x01)
+		for _, u := range units {
+			if xlsMaxRecordData-len(rec) < 2 {
+				flush()
+				rec = append(rec, 0x01)
+			}
+			rec = append(rec, le16(u)...)
+		}
+	}
+	flush()
+	b.record(biffExtSST, append(le16(uint16(dsst)), buckets...))
+}
+
+// shortUnicodeString 字符数为1个字节的UTF-16LE字符串
+func shortUnicodeString(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	data := []byte{byte(len(units)), 0x01}
+	for _, u := range units {
+		data = append(data, le16(u)...)
+	}
+	return data
+}
+
+func le16(values ...uint16) []byte {
+	data := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(data[2*i:], v)
+	}
+	return data
+}
+
+func le32(values ...uint32) []byte {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[4*i:], v)
+	}
+	return data
+}
+
+func le64(v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, v)
+}
+
+// compound file(CFB v3)的常量
+const (
+	cfbSectorSize     = 512
+	cfbMiniCutoff     = 4096 // 小于该长度的流需要保存在mini stream中，所以workbook流至少填充到该长度
+	cfbFreeSect       = 0xFFFFFFFF
+	cfbEndOfChain     = 0xFFFFFFFE
+	cfbFATSect        = 0xFFFFFFFD
+	cfbDIFSect        = 0xFFFFFFFC
+	cfbNoStream       = 0xFFFFFFFF
+	cfbHeaderDIFAT    = 109
+	cfbEntriesPerFAT  = cfbSectorSize / 4
+	cfbEntriesInDIFAT = cfbEntriesPerFAT - 1
+)
+
+// writeCompoundFile 生成只包含一个流的compound file，扇区依次为：流、目录、FAT、DIFAT
+func writeCompoundFile(w io.Writer, name string, stream []byte) error {
+	if len(stream) < cfbMiniCutoff {
+		stream = append(stream, make([]byte, cfbMiniCutoff-len(stream))...)
+	}
+	if len(stream) > math.MaxUint32 {
+		return fmt.Errorf("%w: stream too large", ErrXlsLimitExceeded)
+	}
+	streamSectors := (len(stream) + cfbSectorSize - 1) / cfbSectorSize
+	// FAT需要覆盖所有扇区(包括FAT和DIFAT自身)
+	fatSectors, difatSectors := 0, 0
+	for {
+		total := streamSectors + 1 + fatSectors + difatSectors
+		f := (total + cfbEntriesPerFAT - 1) / cfbEntriesPerFAT
+		d := 0
+		if f > cfbHeaderDIFAT {
+			d = (f - cfbHeaderDIFAT + cfbEntriesInDIFAT - 1) / cfbEntriesInDIFAT
+		}
+		if f == fatSectors && d == difatSectors {
+			break
+		}
+		fatSectors, difatSectors = f, d
+	}
+	dirSector := streamSectors
+	firstFAT := dirSector + 1
+	firstDIFAT := firstFAT + fatSectors
+
+	fat := make([]uint32, fatSectors*cfbEntriesPerFAT)
+	for i := range fat {
+		fat[i] = cfbFreeSect
+	}
+	for i := 0; i < streamSectors; i++ {
+		fat[i] = uint32(i + 1)
+	}
+	fat[streamSectors-1] = cfbEndOfChain
+	fat[dirSector] = cfbEndOfChain
+	for i := 0; i < fatSectors; i++ {
+		fat[firstFAT+i] = cfbFATSect
+	}
+	for i := 0; i < difatSectors; i++ {
+		fat[firstDIFAT+i] = cfbDIFSect
+	}
+
+	header := make([]byte, cfbSectorSize)
+	copy(header, oleSignature)
+	copy(header[24:], le16(0x003E, 0x0003, 0xFFFE, 9, 6))
+	copy(header[44:], le32(uint32(fatSectors), uint32(dirSector), 0, cfbMiniCutoff, cfbEndOfChain, 0))
+	if difatSectors > 0 {
+		copy(header[68:], le32(uint32(firstDIFAT), uint32(difatSectors)))
+	} else {
+		copy(header[68:], le32(cfbEndOfChain, 0))
+	}
+	for i := 0; i < cfbHeaderDIFAT; i++ {
+		v := uint32(cfbFreeSect)
+		if i < fatSectors {
+			v = uint32(firstFAT + i)
+		}
+		copy(header[76+4*i:], le32(v))
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, cfbSectorSize*(2+streamSectors+fatSectors+difatSectors)))
+	buf.Write(header)
+	buf.Write(stream)
+	buf.Write(make([]byte, streamSectors*cfbSectorSize-len(stream)))
+	// 目录：根目录、流、两个空的目录项
+	buf.Write(cfbDirEntry("Root Entry", 5, 1, cfbEndOfChain, 0))
+	buf.Write(cfbDirEntry(name, 2, cfbNoStream, 0, uint32(len(stream))))
+	buf.Write(cfbDirEntry("", 0, cfbNoStream, 0, 0))
+	buf.Write(cfbDirEntry("", 0, cfbNoStream, 0, 0))
+	for _, v := range fat {
+		buf.Write(le32(v))
+	}
+	for i := 0; i < difatSectors; i++ {
+		for j := 0; j < cfbEntriesInDIFAT; j++ {
+			v := uint32(cfbFreeSect)
+			if k := cfbHeaderDIFAT + i*cfbEntriesInDIFAT + j; k < fatSectors {
+				v = uint32(firstFAT + k)
+			}
+			buf.Write(le32(v))
+		}
+		next := uint32(cfbEndOfChain)
+		if i+1 < difatSectors {
+			next = uint32(firstDIFAT + i + 1)
+		}
+		buf.Write(le32(next))
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// cfbDirEntry 128字节的目录项，typ 为0(未使用)、2(流)或5(根目录)
+func cfbDirEntry(name string, typ byte, child, start, size uint32) []byte {
+	entry := make([]byte, 128)
+	if typ != 0 {
+		units := utf16.Encode([]rune(name))
+		for i, u := range units {
+			binary.LittleEndian.PutUint16(entry[2*i:], u)
+		}
+		binary.LittleEndian.PutUint16(entry[64:], uint16(2*len(units)+2))
+		entry[66], entry[67] = typ, 1
+	}
+	copy(entry[68:], le32(cfbNoStream, cfbNoStream, child))
+	copy(entry[116:], le32(start, size, 0))
+	return entry
+}
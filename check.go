@@ -0,0 +1,122 @@
+package eorm
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+type (
+	// CheckField 需要检查的属性名称及其eorm标签
+	CheckField struct {
+		Name string
+		Tag  string
+	}
+
+	// FieldCheck 一个属性与sheet表头的匹配结果，Columns 为匹配到的列下标(升序)，未匹配时为空
+	FieldCheck struct {
+		Name       string     `json:"name" yaml:"name"`
+		Path       TitlePath  `json:"path" yaml:"path"`
+		Encoded    string     `json:"encoded" yaml:"encoded"`
+		Constraint Constraint `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+		Columns    []int      `json:"columns" yaml:"columns"`
+	}
+
+	// CheckReport 一组eorm标签与sheet表头的匹配报告，匹配规则与 NewEORM 相同
+	CheckReport struct {
+		Fields          []FieldCheck `json:"fields" yaml:"fields"`
+		UnmatchedFields []string     `json:"unmatched_fields" yaml:"unmatched_fields"`
+		UnmappedColumns TitleColumns `json:"unmapped_columns" yaml:"unmapped_columns"`
+		PerfectMatch    bool         `json:"perfect_match" yaml:"perfect_match"`
+		Matched         bool         `json:"matched" yaml:"matched"`
+	}
+)
+
+// IsPerfectMatch 每一个属性都找到了对应列，与 EORM.IsPerfectMatch 相同
+func (r *CheckReport) IsPerfectMatch() bool { return r.PerfectMatch }
+
+// IsMatched 至少有一个属性找到了对应列，与 EORM.IsMatched 相同
+func (r *CheckReport) IsMatched() bool { return r.Matched }
+
+// MissingRequired 返回未找到对应列且 constraint 要求必须存在对应列的属性名，不为空时 NewEORM 会返回 ErrRequiredColumnNotFound
+func (r *CheckReport) MissingRequired() []string {
+	var names []string
+	for _, f := range r.Fields {
+		if len(f.Columns) == 0 && f.Constraint.NeedMapper() {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// CheckTitlePaths 检查fields中的eorm标签与sheet表头的匹配情况。表头的深度由标签决定，
+// 标签的深度不一致或者title path重复时返回错误
+func CheckTitlePaths(sheet Sheet, fields []CheckField, opts ...Option) (*CheckReport, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("eorm: no field to check")
+	}
+	params := NewParams(opts...)
+	report := &CheckReport{Fields: make([]FieldCheck, len(fields))}
+	tree := new(PathTree[int])
+	for i, field := range fields {
+		path, constraint, err := ParseTag(field.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("eorm: field %s: %w", field.Name, err)
+		}
+		if err = tree.Put(i, path); err != nil {
+			return nil, fmt.Errorf("eorm: field %s: %w", field.Name, err)
+		}
+		report.Fields[i] = FieldCheck{Name: field.Name, Path: path, Encoded: path.Encode(), Constraint: constraint}
+	}
+	depth, err := tree.Check()
+	if err != nil {
+		return nil, err
+	}
+	columnToField, err := MatchTitlePath(tree, sheet, params)
+	if err != nil {
+		return nil, err
+	}
+	for column, fieldIndex := range columnToField {
+		report.Fields[fieldIndex].Columns = append(report.Fields[fieldIndex].Columns, column)
+	}
+	report.PerfectMatch = true
+	for i := range report.Fields {
+		f := &report.Fields[i]
+		slices.Sort(f.Columns)
+		if len(f.Columns) == 0 {
+			report.UnmatchedFields = append(report.UnmatchedFields, f.Name)
+			report.PerfectMatch = false
+		} else {
+			report.Matched = true
+		}
+	}
+	tcs, err := BuildTitleColumns(sheet, depth, WithParams(params))
+	if err != nil {
+		return nil, err
+	}
+	for _, tc := range tcs {
+		if _, ok := columnToField[tc.Index]; !ok {
+			report.UnmappedColumns = append(report.UnmappedColumns, tc)
+		}
+	}
+	return report, nil
+}
+
+// CheckType 使用objType中属性的eorm标签调用 CheckTitlePaths，属性按声明的顺序排列
+func CheckType(objType reflect.Type, sheet Sheet, opts ...Option) (*CheckReport, error) {
+	schema, err := CompileMapperSchema(objType)
+	if err != nil {
+		return nil, err
+	}
+	var fields []CheckField
+	for _, fieldIndex := range slices.Sorted(maps.Keys(schema.fields)) {
+		m := schema.fields[fieldIndex]
+		tag := m.titlePath.Encode()
+		if m.constraint != ConstraintDefault {
+			tag += "," + string(m.constraint)
+		}
+		fields = append(fields, CheckField{Name: m.fieldName, Tag: tag})
+	}
+	return CheckTitlePaths(sheet, fields, opts...)
+}
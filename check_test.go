@@ -0,0 +1,72 @@
+package eorm
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestCheckTitlePaths(t *testing.T) {
+	wb, err := NewWorkbook(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	sheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := CheckType(reflect.TypeOf(TitleObj2{}), sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := NewEORM[TitleObj2](sheet, reflect.TypeOf(TitleObj2{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.IsPerfectMatch() != em.IsPerfectMatch() || report.IsMatched() != em.IsMatched() {
+		t.Fatalf("report perfect=%t matched=%t, eorm perfect=%t matched=%t",
+			report.IsPerfectMatch(), report.IsMatched(), em.IsPerfectMatch(), em.IsMatched())
+	}
+	var names []string
+	for _, f := range report.Fields {
+		names = append(names, f.Name)
+	}
+	if !slices.Equal(names, []string{"Id", "Name", "Numbers", "Bool", "Slash", "Num", "NoTag1"}) {
+		t.Fatalf("unexpected fields order: %v", names)
+	}
+	if !slices.Equal(report.Fields[2].Columns, []int{6, 7}) {
+		t.Fatalf("expecting Numbers at columns [6 7], got %v", report.Fields[2].Columns)
+	}
+	if !slices.Equal(report.UnmatchedFields, []string{"NoTag1"}) {
+		t.Fatalf("unexpected unmatched fields: %v", report.UnmatchedFields)
+	}
+	if len(report.UnmappedColumns) != 1 || report.UnmappedColumns[0].Letter != "D" {
+		t.Fatalf("unexpected unmapped columns: %+v", report.UnmappedColumns)
+	}
+	if len(report.MissingRequired()) != 0 {
+		t.Fatalf("unexpected missing required: %v", report.MissingRequired())
+	}
+
+	report, err = CheckTitlePaths(sheet, []CheckField{
+		{Name: "id", Tag: "序号//,required"},
+		{Name: "none", Tag: "第一级/不存在/一列,required"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.IsPerfectMatch() || !report.IsMatched() || !slices.Equal(report.MissingRequired(), []string{"none"}) {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(report.UnmappedColumns) != 7 {
+		t.Fatalf("expecting 7 unmapped columns, got %d", len(report.UnmappedColumns))
+	}
+
+	if _, err = CheckTitlePaths(sheet, []CheckField{{Name: "a", Tag: "序号//"}, {Name: "b", Tag: "名称"}}); err == nil {
+		t.Fatal("expecting error for different depths")
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/stephenfire/go-eorm"
+	"github.com/stephenfire/go-eorm/cmds/internal/structinfo"
+)
+
+const eormPath = "github.com/stephenfire/go-eorm"
+
+// generator 使用 structinfo 分析结构体，生成 eorm.RowDecoder
+type generator struct {
+	dir      string
+	withTest bool
+	register bool
+	pkg      *types.Package
+	self     bool              // 生成的代码是否位于eorm包中
+	imports  map[string]string // 生成的代码引用的包 path -> name
+}
+
+func newGenerator(dir string, withTest, register bool) *generator {
+	return &generator{
+		dir:      dir,
+		withTest: withTest,
+		register: register,
+		imports:  make(map[string]string),
+	}
+}
+
+// load 加载目录中的包，withTest 时使用包含 _test.go 文件的版本
+func (g *generator) load() error {
+	pkg, err := structinfo.Load(g.dir, ".", g.withTest)
+	if err != nil {
+		return err
+	}
+	g.pkg = pkg.Types
+	g.self = pkg.PkgPath == eormPath
+	if !g.self {
+		g.imports[eormPath] = "eorm"
+	}
+	return nil
+}
+
+// analyze 分析类型，并检查生成的代码能够引用所有属性的类型
+func (g *generator) analyze(typeName string) (*structinfo.Struct, error) {
+	info, err := structinfo.Analyze(g.pkg, typeName)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range info.Fields {
+		if err = g.checkReferable(f.Type); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, f.Name, err)
+		}
+	}
+	return info, nil
+}
+
+// checkReferable 生成的代码需要引用t(及slice的元素类型)，其他包中未导出的类型无法引用
+func (g *generator) checkReferable(t types.Type) error {
+	if s, ok := t.(*types.Slice); ok {
+		t = s.Elem()
+	}
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() != g.pkg.Path() && !obj.Exported() {
+			return fmt.Errorf("cannot refer to unexported type %s", t)
+		}
+	}
+	return nil
+}
+
+// qualifier 记录生成的代码需要导入的包，包名冲突时使用别名
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg.Path() == g.pkg.Path() {
+		return ""
+	}
+	if name, ok := g.imports[pkg.Path()]; ok {
+		return name
+	}
+	name := pkg.Name()
+	for i := 2; g.importedName(name); i++ {
+		name = pkg.Name() + strconv.Itoa(i)
+	}
+	g.imports[pkg.Path()] = name
+	return name
+}
+
+func (g *generator) importedName(name string) bool {
+	for _, n := range g.imports {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) typeExpr(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+// generate 生成所有类型的decoder，register 时在init()中注册
+func (g *generator) generate(infos []*structinfo.Struct, args []string) ([]byte, error) {
+	q := "eorm."
+	if g.self {
+		q = ""
+	}
+	body := new(bytes.Buffer)
+	if g.register {
+		body.WriteString("func init() {\n")
+		body.WriteString("// 指纹不一致(结构体修改后没有重新生成)时不注册，继续使用反射\n")
+		for _, info := range infos {
+			fmt.Fprintf(body, "_ = %sRegisterDecoder[%s](%s{})\n", q, info.Name, decoderName(info.Name))
+		}
+		body.WriteString("}\n")
+	}
+	for _, info := range infos {
+		g.generateType(body, q, info)
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "// Code generated by \"eormgen %s\"; DO NOT EDIT.\n\n", strings.Join(args, " "))
+	fmt.Fprintf(buf, "package %s\n\n", g.pkg.Name())
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		// 标准库在前，与其他包之间空一行
+		sort.Slice(paths, func(i, j int) bool {
+			if si, sj := isStdPackage(paths[i]), isStdPackage(paths[j]); si != sj {
+				return si
+			}
+			return paths[i] < paths[j]
+		})
+		buf.WriteString("import (\n")
+		for i, path := range paths {
+			if i > 0 && isStdPackage(paths[i-1]) && !isStdPackage(path) {
+				buf.WriteString("\n")
+			}
+			if name := g.imports[path]; name != defaultPackageName(path) {
+				fmt.Fprintf(buf, "%s %q\n", name, path)
+			} else {
+				fmt.Fprintf(buf, "%q\n", path)
+			}
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, buf.String())
+	}
+	return src, nil
+}
+
+// defaultPackageName 导入路径的最后一段，与包名不同(如 go-eorm、excelize/v2)时需要别名
+func defaultPackageName(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func isStdPackage(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func decoderName(typeName string) string {
+	r := []rune(typeName)
+	r[0] = unicode.ToLower(r[0])
+	return string(r) + "Decoder"
+}
+
+func columnFunc(mt eorm.MappingType) string {
+	switch mt {
+	case eorm.MTString, eorm.MTStringSlice:
+		return "ColumnString"
+	case eorm.MTInt64, eorm.MTInt64Slice:
+		return "ColumnInt64"
+	case eorm.MTFloat64, eorm.MTFloat64Slice:
+		return "ColumnFloat64"
+	default:
+		return "ColumnBool"
+	}
+}
+
+// baseType 映射方式对应的基本类型，与之相同时不需要类型转换
+func baseType(mt eorm.MappingType) types.Type {
+	switch mt {
+	case eorm.MTString, eorm.MTStringSlice:
+		return types.Typ[types.String]
+	case eorm.MTInt64, eorm.MTInt64Slice:
+		return types.Typ[types.Int64]
+	case eorm.MTFloat64, eorm.MTFloat64Slice:
+		return types.Typ[types.Float64]
+	default:
+		return types.Typ[types.Bool]
+	}
+}
+
+func (g *generator) convert(t types.Type, mt eorm.MappingType, v string) string {
+	if types.Identical(t, baseType(mt)) {
+		return v
+	}
+	return g.typeExpr(t) + "(" + v + ")"
+}
+
+func (g *generator) generateType(buf *bytes.Buffer, q string, info *structinfo.Struct) {
+	name := decoderName(info.Name)
+	fmt.Fprintf(buf, "\n// %s 由 eormgen 生成的 %s 的 %sRowDecoder\n", name, info.Name, q)
+	fmt.Fprintf(buf, "type %s struct{}\n\n", name)
+	fmt.Fprintf(buf, "func (%s) DecodeRow(row %sRow, columns [][]int, params *%sParams) (*%s, error) {\n",
+		name, q, q, info.Name)
+	buf.WriteString("obj := new(" + info.Name + ")\n")
+	for _, f := range info.Fields {
+		constraint := strconv.Quote(string(f.Constraint))
+		fmt.Fprintf(buf, "// %s: %s\n", f.Name, strings.ReplaceAll(f.Tag, "\n", " "))
+		fmt.Fprintf(buf, "if cols := columns[%d]; len(cols) > 0 {\n", f.Index)
+		var value string
+		if f.Mapping.IsSlice() {
+			fmt.Fprintf(buf, "vs := make(%s, len(cols))\n", g.typeExpr(f.Type))
+			buf.WriteString("for i, col := range cols {\n")
+			fmt.Fprintf(buf, "v, err := %s%s(row, col, %s, params)\n", q, columnFunc(f.Mapping), constraint)
+			buf.WriteString("if err != nil {\nreturn nil, err\n}\n")
+			fmt.Fprintf(buf, "vs[i] = %s\n", g.convert(f.Elem, f.Mapping, "v"))
+			buf.WriteString("}\n")
+			value = "vs"
+		} else {
+			fmt.Fprintf(buf, "v, err := %s%s(row, cols[0], %s, params)\n", q, columnFunc(f.Mapping), constraint)
+			buf.WriteString("if err != nil {\nreturn nil, err\n}\n")
+			value = g.convert(f.Type, f.Mapping, "v")
+		}
+		if f.Setter != "" {
+			fmt.Fprintf(buf, "obj.%s(%s)\n", f.Setter, value)
+		} else {
+			fmt.Fprintf(buf, "obj.%s = %s\n", f.Name, value)
+		}
+		buf.WriteString("}\n")
+	}
+	buf.WriteString("return obj, nil\n}\n\n")
+	fmt.Fprintf(buf, "func (%s) Fingerprint() string {\nreturn %q\n}\n", name, info.Fingerprint)
+}
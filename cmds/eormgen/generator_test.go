@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// checkGolden 比较生成的代码与golden文件，-update 时更新golden文件
+func checkGolden(t *testing.T, golden string, src []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(golden, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Fatalf("%s is stale, run \"go test -update\" or \"go generate\", got:\n%s", golden, src)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *options
+		args     []string
+		checkPkg bool // 生成的代码在包中，检查包能够通过编译
+	}{
+		{
+			// 嵌入属性提升的Set方法、被忽略的Set方法、命名及别名的元素类型、包名冲突及与路径不同时的别名
+			name: "basic",
+			opts: &options{types: []string{"Item", "Pair"}, dir: filepath.Join("testdata", "basic"),
+				output: filepath.Join("testdata", "basic", "item_eorm.go"), register: true},
+			args:     []string{"--type", "Item,Pair"},
+			checkPkg: true,
+		},
+	}
+	for _, tt := range tests {
+		src, err := tt.opts.generate(tt.args)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		checkGolden(t, tt.opts.output, src)
+		if !tt.checkPkg {
+			continue
+		}
+		cmd := exec.Command("go", "vet", ".")
+		cmd.Dir = tt.opts.dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%s: generated code does not compile: %v\n%s", tt.name, err, out)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		typ string
+		err string
+	}{
+		{"Unexported", "Unexported.name: unexported field without setter is not settable"},
+		{"Unsupported", "Unsupported.Values: unsupported type"},
+		{"Variadic", "Variadic.Names: variadic setter SetNames is not supported"},
+		{"Unreferable", "Unreferable.Value: cannot refer to unexported type"},
+		{"Duplicated", "Duplicated.B: "},
+		{"NotStruct", "type NotStruct is not a struct"},
+		{"Generic", "generic type Generic is not supported"},
+		{"Alias", "type Alias is an alias"},
+		{"Missing", "type Missing not found"},
+	}
+	for _, tt := range tests {
+		opts := &options{types: []string{tt.typ}, dir: filepath.Join("testdata", "invalid")}
+		if _, err := opts.generate(nil); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Fatalf("%s: expecting error %q, got %v", tt.typ, tt.err, err)
+		}
+	}
+}
+
+// TestGenerated 根目录中由 go:generate 生成的代码与当前的生成结果一致
+func TestGenerated(t *testing.T) {
+	root := filepath.Join("..", "..")
+	args := goGenerateArgs(t, filepath.Join(root, "eorm_test.go"))
+	var opts *options
+	app := newApp()
+	app.Action = func(ctx *cli.Context) (err error) {
+		opts, err = parseOptions(ctx)
+		return err
+	}
+	if err := app.Run(append([]string{"eormgen"}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	// go:generate 在根目录中运行
+	opts.dir, opts.output = root, filepath.Join(root, opts.output)
+	src, err := opts.generate(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(opts.output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Fatalf("%s is stale, run \"go generate\" in the module root", opts.output)
+	}
+}
+
+// goGenerateArgs 返回file中运行eormgen的go:generate指令的参数
+func goGenerateArgs(t *testing.T, file string) []string {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if args, ok := strings.CutPrefix(scanner.Text(), "//go:generate go run ./cmds/eormgen "); ok {
+			return strings.Fields(args)
+		}
+	}
+	t.Fatalf("no eormgen go:generate directive found in %s", file)
+	return nil
+}
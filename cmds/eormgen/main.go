@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stephenfire/go-common/log"
+	"github.com/stephenfire/go-eorm"
+	"github.com/stephenfire/go-eorm/cmds/internal/structinfo"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	typeFlag = &cli.StringSliceFlag{
+		Name:     "type",
+		Usage:    "comma-separated list of struct `TYPE` names, required",
+		Required: true,
+		Aliases:  []string{"t"},
+	}
+
+	outputFlag = &cli.StringFlag{
+		Name:    "output",
+		Usage:   "output `FILE` name, default <first type>_eorm.go (or <first type>_eorm_test.go with --tests) in the package directory",
+		Aliases: []string{"o"},
+	}
+
+	testsFlag = &cli.BoolFlag{
+		Name:    "tests",
+		Usage:   "include _test.go files, for types declared in tests",
+		Value:   false,
+		Aliases: []string{"T"},
+	}
+
+	noRegisterFlag = &cli.BoolFlag{
+		Name:  "no-register",
+		Usage: "do not register the decoders in init(), register them with eorm.RegisterDecoder instead",
+		Value: false,
+	}
+
+	allFlags = []cli.Flag{
+		typeFlag,
+		outputFlag,
+		testsFlag,
+		noRegisterFlag,
+	}
+)
+
+// options 命令行参数
+type options struct {
+	types    []string
+	dir      string
+	output   string
+	withTest bool
+	register bool
+}
+
+func main() {
+	if err := newApp().Run(os.Args); err != nil {
+		log.Errorf("exit from main: %v", err)
+		os.Exit(1)
+	}
+}
+
+func newApp() *cli.App {
+	app := &cli.App{
+		Name:      "eormgen",
+		Usage:     "generate reflection-free eorm.RowDecoder for structs with eorm tags",
+		UsageText: "eormgen --type T[,T...] [--output FILE] [--tests] [--no-register] [DIR]\n\n//go:generate go run github.com/stephenfire/go-eorm/cmds/eormgen --type T",
+		Version:   eorm.Version.String(),
+		Copyright: eorm.Copyright,
+		Flags:     allFlags,
+		Action:    eormgen,
+	}
+	sort.Sort(cli.FlagsByName(app.Flags))
+	return app
+}
+
+func eormgen(ctx *cli.Context) error {
+	opts, err := parseOptions(ctx)
+	if err != nil {
+		return err
+	}
+	src, err := opts.generate(os.Args[1:])
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(opts.output, src, 0o644)
+}
+
+func parseOptions(ctx *cli.Context) (*options, error) {
+	opts := &options{
+		dir:      ".",
+		withTest: ctx.Bool(testsFlag.Name),
+		register: !ctx.Bool(noRegisterFlag.Name),
+	}
+	for _, t := range ctx.StringSlice(typeFlag.Name) {
+		for _, name := range strings.Split(t, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				opts.types = append(opts.types, name)
+			}
+		}
+	}
+	if len(opts.types) == 0 {
+		return nil, fmt.Errorf("no type specified")
+	}
+	if ctx.NArg() > 0 {
+		opts.dir = ctx.Args().First()
+	}
+	opts.output = ctx.String(outputFlag.Name)
+	if opts.output == "" {
+		suffix := "_eorm.go"
+		if opts.withTest {
+			suffix = "_eorm_test.go"
+		}
+		opts.output = strings.ToLower(opts.types[0]) + suffix
+	}
+	if !filepath.IsAbs(opts.output) && filepath.Dir(opts.output) == "." {
+		opts.output = filepath.Join(opts.dir, opts.output)
+	}
+	return opts, nil
+}
+
+// generate 生成所有类型的decoder，args 为记录在生成代码中的命令行参数
+func (o *options) generate(args []string) ([]byte, error) {
+	g := newGenerator(o.dir, o.withTest, o.register)
+	if err := g.load(); err != nil {
+		return nil, err
+	}
+	var infos []*structinfo.Struct
+	for _, name := range o.types {
+		info, err := g.analyze(name)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return g.generate(infos, args)
+}
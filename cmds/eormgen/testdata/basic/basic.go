@@ -0,0 +1,46 @@
+package basic
+
+import (
+	"math/big"
+
+	"github.com/stephenfire/go-eorm/cmds/eormgen/testdata/basic/eorm"
+	units "github.com/stephenfire/go-eorm/cmds/eormgen/testdata/basic/v2"
+)
+
+type (
+	Integer int64
+	Flag    = bool
+	Tags    []string
+
+	// Base 嵌入属性的Set方法被提升到 *Item 的方法集中
+	Base struct {
+		code string
+	}
+
+	Item struct {
+		Base
+		ID     Integer      `eorm:"编号,required"`
+		Counts []Integer    `eorm:"数量"`
+		Flags  []Flag       `eorm:"标记"`
+		Code   string       `eorm:"代码"`
+		Amount float64      `eorm:"金额,not_null"`
+		Tags   Tags         `eorm:"标签"`
+		Level  eorm.Level   `eorm:"级别"`
+		Unit   units.Unit   `eorm:"单位"`
+		Sizes  []units.Unit `eorm:"尺寸"`
+		Skip   string
+	}
+
+	// Pair 多个类型生成在同一个文件中
+	Pair struct {
+		Key   string `eorm:"键"`
+		Value *big.Int
+	}
+)
+
+func (b *Base) SetCode(code string) { b.code = code }
+
+// SetAmount 参数类型不能映射，与反射一致被忽略，直接为属性赋值
+func (i *Item) SetAmount(v *big.Int) { i.Amount, _ = new(big.Float).SetInt(v).Float64() }
+
+func (i *Item) SetTags(tags []string) { i.Tags = tags }
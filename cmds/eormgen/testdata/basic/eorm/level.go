@@ -0,0 +1,4 @@
+// Package eorm 与 go-eorm 同名，生成的代码需要为其中一个使用别名
+package eorm
+
+type Level int64
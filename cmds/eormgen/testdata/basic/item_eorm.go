@@ -0,0 +1,135 @@
+// Code generated by "eormgen --type Item,Pair"; DO NOT EDIT.
+
+package basic
+
+import (
+	eorm "github.com/stephenfire/go-eorm"
+	eorm2 "github.com/stephenfire/go-eorm/cmds/eormgen/testdata/basic/eorm"
+	units "github.com/stephenfire/go-eorm/cmds/eormgen/testdata/basic/v2"
+)
+
+func init() {
+	// 指纹不一致(结构体修改后没有重新生成)时不注册，继续使用反射
+	_ = eorm.RegisterDecoder[Item](itemDecoder{})
+	_ = eorm.RegisterDecoder[Pair](pairDecoder{})
+}
+
+// itemDecoder 由 eormgen 生成的 Item 的 eorm.RowDecoder
+type itemDecoder struct{}
+
+func (itemDecoder) DecodeRow(row eorm.Row, columns [][]int, params *eorm.Params) (*Item, error) {
+	obj := new(Item)
+	// ID: 编号,required
+	if cols := columns[1]; len(cols) > 0 {
+		v, err := eorm.ColumnInt64(row, cols[0], "required", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.ID = Integer(v)
+	}
+	// Counts: 数量
+	if cols := columns[2]; len(cols) > 0 {
+		vs := make([]Integer, len(cols))
+		for i, col := range cols {
+			v, err := eorm.ColumnInt64(row, col, "", params)
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = Integer(v)
+		}
+		obj.Counts = vs
+	}
+	// Flags: 标记
+	if cols := columns[3]; len(cols) > 0 {
+		vs := make([]Flag, len(cols))
+		for i, col := range cols {
+			v, err := eorm.ColumnBool(row, col, "", params)
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = v
+		}
+		obj.Flags = vs
+	}
+	// Code: 代码
+	if cols := columns[4]; len(cols) > 0 {
+		v, err := eorm.ColumnString(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.SetCode(v)
+	}
+	// Amount: 金额,not_null
+	if cols := columns[5]; len(cols) > 0 {
+		v, err := eorm.ColumnFloat64(row, cols[0], "not_null", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Amount = v
+	}
+	// Tags: 标签
+	if cols := columns[6]; len(cols) > 0 {
+		vs := make([]string, len(cols))
+		for i, col := range cols {
+			v, err := eorm.ColumnString(row, col, "", params)
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = v
+		}
+		obj.SetTags(vs)
+	}
+	// Level: 级别
+	if cols := columns[7]; len(cols) > 0 {
+		v, err := eorm.ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Level = eorm2.Level(v)
+	}
+	// Unit: 单位
+	if cols := columns[8]; len(cols) > 0 {
+		v, err := eorm.ColumnString(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Unit = units.Unit(v)
+	}
+	// Sizes: 尺寸
+	if cols := columns[9]; len(cols) > 0 {
+		vs := make([]units.Unit, len(cols))
+		for i, col := range cols {
+			v, err := eorm.ColumnString(row, col, "", params)
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = units.Unit(v)
+		}
+		obj.Sizes = vs
+	}
+	return obj, nil
+}
+
+func (itemDecoder) Fingerprint() string {
+	return "b8c6a74982e81e85"
+}
+
+// pairDecoder 由 eormgen 生成的 Pair 的 eorm.RowDecoder
+type pairDecoder struct{}
+
+func (pairDecoder) DecodeRow(row eorm.Row, columns [][]int, params *eorm.Params) (*Pair, error) {
+	obj := new(Pair)
+	// Key: 键
+	if cols := columns[0]; len(cols) > 0 {
+		v, err := eorm.ColumnString(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Key = v
+	}
+	return obj, nil
+}
+
+func (pairDecoder) Fingerprint() string {
+	return "6b23bc38cb99d97f"
+}
@@ -0,0 +1,4 @@
+// Package units 包名与导入路径的最后一段不同
+package units
+
+type Unit string
@@ -0,0 +1,8 @@
+package inner
+
+type (
+	hidden int64
+
+	// Visible 其他包无法引用别名指向的未导出类型
+	Visible = hidden
+)
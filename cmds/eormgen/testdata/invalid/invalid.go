@@ -0,0 +1,36 @@
+package invalid
+
+import "github.com/stephenfire/go-eorm/cmds/eormgen/testdata/invalid/inner"
+
+type (
+	Unexported struct {
+		name string `eorm:"名称"`
+	}
+
+	Unsupported struct {
+		Values map[string]int64 `eorm:"值"`
+	}
+
+	Variadic struct {
+		Names []string `eorm:"名称"`
+	}
+
+	Unreferable struct {
+		Value inner.Visible `eorm:"值"`
+	}
+
+	Duplicated struct {
+		A string `eorm:"名称"`
+		B string `eorm:"名称"`
+	}
+
+	NotStruct int64
+
+	Generic[T any] struct {
+		Value T `eorm:"值"`
+	}
+
+	Alias = Unsupported
+)
+
+func (v *Variadic) SetNames(names ...string) { v.Names = names }
@@ -0,0 +1,193 @@
+// Package structinfo 通过 go/packages 加载包并进行类型检查，按照 eorm.CompileMapperSchema 使用反射的规则
+// (底层类型、*T的方法集中包括嵌入属性提升的Set方法)分析结构体中带有eorm标签的属性，供 eormgen 和 pathgener 共用
+package structinfo
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/stephenfire/go-eorm"
+	"golang.org/x/tools/go/packages"
+)
+
+// ErrUnsupportedType 不能映射的类型，与 eorm.NewMappingType 一致
+var ErrUnsupportedType = errors.New("unsupported type")
+
+type (
+	// Field 带有eorm标签的属性
+	Field struct {
+		Index      int
+		Name       string
+		Tag        string
+		Constraint eorm.Constraint
+		Mapping    eorm.MappingType
+		Setter     string     // setter方法名，为空时直接赋值
+		Type       types.Type // 属性类型，或setter的参数类型
+		Elem       types.Type // slice类型的元素类型
+	}
+
+	// Struct 结构体中按声明顺序排列的所有带有eorm标签的属性
+	Struct struct {
+		Name        string
+		Fields      []*Field
+		Fingerprint string // 与 eorm.MapperSchema.Fingerprint 一致
+	}
+)
+
+// Load 加载dir中pattern对应的包，withTest 时使用包含 _test.go 文件的版本(不包括外部测试包)。
+// 包中的类型错误(如过期的生成代码)只在影响需要分析的类型时报告
+func Load(dir, pattern string, withTest bool) (*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedSyntax,
+		Dir:   dir,
+		Tests: withTest,
+	}
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+	var pkg *packages.Package
+	for _, p := range pkgs {
+		if strings.HasSuffix(p.Name, "_test") || strings.HasSuffix(p.PkgPath, ".test") {
+			continue
+		}
+		// 测试版本的ID为 "path [path.test]"，包含 _test.go 中的声明
+		if pkg == nil || strings.Contains(p.ID, "[") {
+			pkg = p
+		}
+	}
+	if pkg == nil || pkg.Types == nil {
+		return nil, fmt.Errorf("no go package found for %s in %s", pattern, dir)
+	}
+	for _, e := range pkg.Errors {
+		if e.Kind != packages.TypeError {
+			return nil, fmt.Errorf("load package %s: %v", pkg.PkgPath, e)
+		}
+	}
+	return pkg, nil
+}
+
+// MappingType 与 eorm.NewMappingType 一致，根据类型的底层类型确定映射方式，slice类型同时返回元素类型
+func MappingType(t types.Type) (eorm.MappingType, types.Type, error) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		if u.Kind() == types.Invalid {
+			return eorm.MTInvalid, nil, fmt.Errorf("invalid type %s, check the type errors of the package", t)
+		}
+		if mt := basicMapping(u); mt != eorm.MTInvalid {
+			return mt, nil, nil
+		}
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok {
+			switch basicMapping(b) {
+			case eorm.MTString:
+				return eorm.MTStringSlice, u.Elem(), nil
+			case eorm.MTInt64:
+				return eorm.MTInt64Slice, u.Elem(), nil
+			case eorm.MTFloat64:
+				return eorm.MTFloat64Slice, u.Elem(), nil
+			case eorm.MTBool:
+				return eorm.MTBoolSlice, u.Elem(), nil
+			}
+		}
+	}
+	return eorm.MTInvalid, nil, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+}
+
+func basicMapping(b *types.Basic) eorm.MappingType {
+	switch b.Kind() {
+	case types.String:
+		return eorm.MTString
+	case types.Int64:
+		return eorm.MTInt64
+	case types.Float64:
+		return eorm.MTFloat64
+	case types.Bool:
+		return eorm.MTBool
+	default:
+		return eorm.MTInvalid
+	}
+}
+
+// Analyze 与 eorm.CompileMapperSchema 一致，找到所有带有eorm标签的属性，*T的方法集中存在合法的Set方法时使用Set方法
+func Analyze(pkg *types.Package, typeName string) (*Struct, error) {
+	tn, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typeName, pkg.Path())
+	}
+	named, ok := types.Unalias(tn.Type()).(*types.Named)
+	if !ok || named.Obj() != tn {
+		return nil, fmt.Errorf("type %s is an alias, use the aliased type instead", typeName)
+	}
+	if named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("generic type %s is not supported", typeName)
+	}
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a struct", typeName)
+	}
+	methods := types.NewMethodSet(types.NewPointer(named))
+	info := &Struct{Name: typeName}
+	paths := new(eorm.PathTree[int])
+	var signatures []eorm.FieldSignature
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag, ok := reflect.StructTag(st.Tag(i)).Lookup("eorm")
+		if !ok {
+			continue
+		}
+		f, err := analyzeField(methods, i, field, tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, field.Name(), err)
+		}
+		tp, _, _ := eorm.ParseTag(tag)
+		if err = paths.Put(i, tp); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, field.Name(), err)
+		}
+		info.Fields = append(info.Fields, f)
+		signatures = append(signatures, eorm.FieldSignature{
+			Index: i, Name: f.Name, Tag: tag, Mapping: f.Mapping, Setter: f.Setter})
+	}
+	info.Fingerprint = eorm.Fingerprint(signatures)
+	return info, nil
+}
+
+func analyzeField(methods *types.MethodSet, index int, field *types.Var, tag string) (*Field, error) {
+	_, constraint, err := eorm.ParseTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	// 嵌入属性的名称为其类型名，与反射一致
+	f := &Field{Index: index, Name: field.Name(), Tag: tag, Constraint: constraint}
+
+	if sel := methods.Lookup(nil, "Set"+field.Name()); sel != nil {
+		sig := sel.Obj().Type().(*types.Signature)
+		if sig.Params().Len() == 1 {
+			if sig.Variadic() {
+				// 反射方式调用可变参数的方法时会把slice作为一个参数，所以不支持
+				return nil, fmt.Errorf("variadic setter %s is not supported", sel.Obj().Name())
+			}
+			param := sig.Params().At(0).Type()
+			// 与反射方式一致，参数类型不支持时忽略该Set方法
+			if mt, elem, err := MappingType(param); err == nil {
+				f.Setter, f.Mapping, f.Type, f.Elem = sel.Obj().Name(), mt, param, elem
+				return f, nil
+			} else if !errors.Is(err, ErrUnsupportedType) {
+				return nil, err
+			}
+		}
+	}
+
+	if !field.Exported() {
+		return nil, errors.New("unexported field without setter is not settable")
+	}
+	mt, elem, err := MappingType(field.Type())
+	if err != nil {
+		return nil, err
+	}
+	f.Mapping, f.Type, f.Elem = mt, field.Type(), elem
+	return f, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/stephenfire/go-eorm"
+	"github.com/stephenfire/go-eorm/cmds/internal/structinfo"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	tagFlag = &cli.StringSliceFlag{
+		Name:    "tag",
+		Usage:   "eorm `TAG` to check, can be repeated, e.g. --tag '序号//' --tag '第一级/第二级/第三级,required'",
+		Aliases: []string{"g"},
+	}
+
+	pkgFlag = &cli.StringFlag{
+		Name:  "pkg",
+		Usage: "go/packages `PATTERN` of the package declaring --type, including its test files",
+		Value: ".",
+	}
+
+	typeFlag = &cli.StringFlag{
+		Name:  "type",
+		Usage: "struct type `NAME` whose eorm tags will be checked",
+	}
+
+	checkCommand = &cli.Command{
+		Name:      "check",
+		Usage:     "check eorm tags against the title of the sheet",
+		UsageText: "pathgener --file FILE [OPTIONS] check (--tag TAG [--tag TAG...] | [--pkg PATTERN] --type NAME)",
+		Flags:     []cli.Flag{tagFlag, pkgFlag, typeFlag, formatFlag},
+		Action:    check,
+	}
+)
+
+func check(ctx *cli.Context) error {
+	if err := requireFlags(ctx, fileFlag); err != nil {
+		return err
+	}
+	var fields []eorm.CheckField
+	for _, tag := range ctx.StringSlice(tagFlag.Name) {
+		fields = append(fields, eorm.CheckField{Name: tag, Tag: tag})
+	}
+	if typeName := ctx.String(typeFlag.Name); typeName != "" {
+		typeFields, err := loadTypeFields(ctx.String(pkgFlag.Name), typeName)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, typeFields...)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("either --%s or --%s is required", tagFlag.Name, typeFlag.Name)
+	}
+	wb, sheet, err := openSheet(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	report, err := eorm.CheckTitlePaths(sheet, fields, titleOptions(ctx)...)
+	if err != nil {
+		return err
+	}
+	return writeCheckReport(ctx.App.Writer, outputFormat(ctx), report)
+}
+
+// loadTypeFields 通过 go/packages 加载pattern对应的包(包括测试文件)并进行类型检查，
+// 与 eormgen 相同，按照 eorm.CompileMapperSchema 的规则返回typeName中所有带有eorm标签的属性
+func loadTypeFields(pattern, typeName string) ([]eorm.CheckField, error) {
+	pkg, err := structinfo.Load("", pattern, true)
+	if err != nil {
+		return nil, err
+	}
+	info, err := structinfo.Analyze(pkg.Types, typeName)
+	if err != nil {
+		return nil, err
+	}
+	if len(info.Fields) == 0 {
+		return nil, fmt.Errorf("no field with eorm tag in %s.%s", pkg.PkgPath, typeName)
+	}
+	fields := make([]eorm.CheckField, len(info.Fields))
+	for i, f := range info.Fields {
+		fields[i] = eorm.CheckField{Name: f.Name, Tag: f.Tag}
+	}
+	return fields, nil
+}
+
+func writeCheckReport(w io.Writer, format string, report *eorm.CheckReport) error {
+	switch strings.ToLower(format) {
+	case formatText, "":
+		return writeCheckReportText(w, report)
+	case formatJSON, formatYAML:
+		return encode(w, format, report)
+	default:
+		return fmt.Errorf("unsupported format %q for check", format)
+	}
+}
+
+func writeCheckReportText(w io.Writer, report *eorm.CheckReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tCOLUMNS\tTAG")
+	for _, f := range report.Fields {
+		columns := "-"
+		if len(f.Columns) > 0 {
+			letters := make([]string, len(f.Columns))
+			for i, c := range f.Columns {
+				letters[i] = eorm.ColumnLetter(c)
+			}
+			columns = strings.Join(letters, ",")
+		}
+		tag := f.Encoded
+		if f.Constraint != eorm.ConstraintDefault {
+			tag += "," + string(f.Constraint)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", f.Name, columns, tag)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Unmatched fields: %s\n", joinOrNone(report.UnmatchedFields))
+	if missing := report.MissingRequired(); len(missing) > 0 {
+		fmt.Fprintf(w, "Missing required: %s\n", strings.Join(missing, ", "))
+	}
+	columns := make([]string, len(report.UnmappedColumns))
+	for i, tc := range report.UnmappedColumns {
+		columns[i] = fmt.Sprintf("%s[%s]", tc.Letter, tc.Encoded)
+	}
+	fmt.Fprintf(w, "Unmapped columns: %s\n", joinOrNone(columns))
+	fmt.Fprintf(w, "IsPerfectMatch: %t\n", report.IsPerfectMatch())
+	_, err := fmt.Fprintf(w, "IsMatched: %t\n", report.IsMatched())
+	return err
+}
+
+func joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
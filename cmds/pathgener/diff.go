@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stephenfire/go-eorm"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	oldSheetFlag = &cli.StringFlag{
+		Name:  "old-sheet",
+		Usage: "the `SHEET` name in the old file, default the first sheet",
+	}
+
+	newSheetFlag = &cli.StringFlag{
+		Name:  "new-sheet",
+		Usage: "the `SHEET` name in the new file, default the first sheet",
+	}
+
+	strictFlag = &cli.BoolFlag{
+		Name:  "strict",
+		Usage: "treat added and reordered columns as breaking changes too",
+	}
+
+	diffCommand = &cli.Command{
+		Name:  "diff",
+		Usage: "compare the title paths of two files or two sheets, exit with code 2 on breaking changes",
+		UsageText: "pathgener --depth DEPTH [OPTIONS] diff [--old-sheet SHEET] [--new-sheet SHEET] [--strict] OLD_FILE [NEW_FILE]\n\n" +
+			"NEW_FILE defaults to OLD_FILE, to compare two sheets of the same file",
+		Flags:  []cli.Flag{oldSheetFlag, newSheetFlag, strictFlag, formatFlag},
+		Action: diff,
+	}
+)
+
+func diff(ctx *cli.Context) error {
+	if err := requireFlags(ctx, depthFlag); err != nil {
+		return err
+	}
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return fmt.Errorf("expecting OLD_FILE [NEW_FILE]")
+	}
+	oldFile, newFile := ctx.Args().Get(0), ctx.Args().Get(0)
+	if ctx.NArg() > 1 {
+		newFile = ctx.Args().Get(1)
+	}
+	oldPaths, err := loadTitlePaths(ctx, oldFile, ctx.String(oldSheetFlag.Name))
+	if err != nil {
+		return fmt.Errorf("old: %w", err)
+	}
+	newPaths, err := loadTitlePaths(ctx, newFile, ctx.String(newSheetFlag.Name))
+	if err != nil {
+		return fmt.Errorf("new: %w", err)
+	}
+	d := eorm.DiffTitlePaths(oldPaths, newPaths)
+	if err = writeTitleDiff(ctx.App.Writer, outputFormat(ctx), d); err != nil {
+		return err
+	}
+	if d.IsBreaking() || (ctx.Bool(strictFlag.Name) && !d.IsEmpty()) {
+		return cli.Exit("breaking changes found", 2)
+	}
+	return nil
+}
+
+func loadTitlePaths(ctx *cli.Context, filename, sheetname string) (eorm.TitlePaths, error) {
+	wb, err := eorm.NewWorkbook(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	var sheet eorm.Sheet
+	if sheetname != "" {
+		sheet, err = eorm.GetSheetByNameContext(ctx.Context, wb, sheetname)
+	} else {
+		sheet, err = eorm.GetSheetContext(ctx.Context, wb, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return eorm.BuildTitlePaths(sheet, ctx.Int(depthFlag.Name), titleOptions(ctx)...)
+}
+
+func writeTitleDiff(w io.Writer, format string, d *eorm.TitleDiff) error {
+	switch strings.ToLower(format) {
+	case formatText, "":
+		if d.IsEmpty() {
+			_, err := fmt.Fprintln(w, "no changes")
+			return err
+		}
+		column := func(index int) string {
+			return fmt.Sprintf("%s(%d)", eorm.ColumnLetter(index), index+1)
+		}
+		for _, c := range d.Removed {
+			fmt.Fprintf(w, "- removed   %-8s [%s]\n", column(c.OldIndex), c.Old)
+		}
+		for _, c := range d.Renamed {
+			fmt.Fprintf(w, "~ renamed   %-8s [%s] -> [%s]\n", column(c.OldIndex), c.Old, c.New)
+		}
+		for _, c := range d.Added {
+			fmt.Fprintf(w, "+ added     %-8s [%s]\n", column(c.NewIndex), c.New)
+		}
+		for _, c := range d.Reordered {
+			fmt.Fprintf(w, "> reordered %-8s [%s] -> %s\n", column(c.OldIndex), c.Old, column(c.NewIndex))
+		}
+		_, err := fmt.Fprintf(w, "breaking: %t\n", d.IsBreaking())
+		return err
+	case formatJSON, formatYAML:
+		return encode(w, format, d)
+	default:
+		return fmt.Errorf("unsupported format %q for diff", format)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/stephenfire/go-eorm"
+	"github.com/urfave/cli/v2"
+)
+
+const formatJSONL = "jsonl"
+
+var (
+	joinerFlag = &cli.StringFlag{
+		Name:  "joiner",
+		Usage: "`SEP` used to join the titles of a column as the csv header",
+		Value: "/",
+	}
+
+	dumpCommand = &cli.Command{
+		Name:      "dump",
+		Usage:     "dump the data rows of the sheet as JSON Lines keyed by encoded title path, or as csv",
+		UsageText: "pathgener --file FILE --depth DEPTH [OPTIONS] dump [--format jsonl|csv] [--joiner SEP] [--output FILE]",
+		Flags:     []cli.Flag{formatFlag, joinerFlag, outputFlag},
+		Action:    dump,
+	}
+)
+
+func dump(ctx *cli.Context) error {
+	if err := requireFlags(ctx, fileFlag, depthFlag); err != nil {
+		return err
+	}
+	format := strings.ToLower(outputFormat(ctx))
+	switch format {
+	case formatText, formatJSON:
+		format = formatJSONL
+	case formatJSONL, formatCSV:
+	default:
+		return fmt.Errorf("unsupported format %q for dump", format)
+	}
+	wb, sheet, err := openSheet(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	depth := ctx.Int(depthFlag.Name)
+	opts := titleOptions(ctx)
+	tps, err := eorm.BuildTitlePaths(sheet, depth, opts...)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = ctx.App.Writer
+	if output := ctx.String(outputFlag.Name); output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		out = f
+	}
+	bw := bufio.NewWriter(out)
+	var w rowWriter
+	if format == formatCSV {
+		w, err = newCSVRowWriter(bw, tps, ctx.String(joinerFlag.Name))
+	} else {
+		w, err = newJSONLRowWriter(bw, tps)
+	}
+	if err != nil {
+		return err
+	}
+
+	params := eorm.NewParams(opts...)
+	values := make([]string, len(tps))
+	for i := params.MinRows(depth); i < sheet.RowCount(); i++ {
+		row, err := eorm.GetRowContext(ctx.Context, sheet, i)
+		if err != nil {
+			if errors.Is(err, eorm.ErrRowNotFound) {
+				continue
+			}
+			return fmt.Errorf("get row %d: %w", i, err)
+		}
+		for j := range values {
+			v, err := row.GetColumn(j)
+			if err != nil && !errors.Is(err, eorm.ErrEmptyCell) && !errors.Is(err, eorm.ErrOutOfRange) {
+				return fmt.Errorf("get row %d column %d: %w", i, j, err)
+			}
+			if params.TrimSpace {
+				v = strings.TrimSpace(v)
+			}
+			values[j] = v
+		}
+		if err = w.write(values); err != nil {
+			return err
+		}
+	}
+	if err = w.flush(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+type (
+	// rowWriter 按表头的列顺序输出一个数据行
+	rowWriter interface {
+		write(values []string) error
+		flush() error
+	}
+
+	// jsonlRowWriter 每行输出一个以编码后的title path为key的JSON对象，key的顺序与列的顺序一致。
+	// 重复的title path对应多列，其值为这些列的值组成的数组
+	jsonlRowWriter struct {
+		w       *bufio.Writer
+		keys    [][]byte
+		columns [][]int
+	}
+
+	csvRowWriter struct {
+		w *csv.Writer
+	}
+)
+
+func newJSONLRowWriter(w *bufio.Writer, tps eorm.TitlePaths) (*jsonlRowWriter, error) {
+	jw := &jsonlRowWriter{w: w}
+	positions := make(map[string]int)
+	for i, tp := range tps {
+		key := tp.Encode()
+		if pos, ok := positions[key]; ok {
+			jw.columns[pos] = append(jw.columns[pos], i)
+			continue
+		}
+		k, err := marshalJSON(key)
+		if err != nil {
+			return nil, err
+		}
+		positions[key] = len(jw.keys)
+		jw.keys = append(jw.keys, k)
+		jw.columns = append(jw.columns, []int{i})
+	}
+	return jw, nil
+}
+
+func (jw *jsonlRowWriter) write(values []string) error {
+	jw.w.WriteByte('{')
+	for i, key := range jw.keys {
+		if i > 0 {
+			jw.w.WriteByte(',')
+		}
+		jw.w.Write(key)
+		jw.w.WriteByte(':')
+		var v any
+		if columns := jw.columns[i]; len(columns) == 1 {
+			v = values[columns[0]]
+		} else {
+			vs := make([]string, len(columns))
+			for k, c := range columns {
+				vs[k] = values[c]
+			}
+			v = vs
+		}
+		bs, err := marshalJSON(v)
+		if err != nil {
+			return err
+		}
+		jw.w.Write(bs)
+	}
+	jw.w.WriteByte('}')
+	return jw.w.WriteByte('\n')
+}
+
+func (jw *jsonlRowWriter) flush() error { return nil }
+
+// marshalJSON 与 json.Marshal 相同，但不转义HTML字符
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+// newCSVRowWriter 使用joiner连接每列的非空标题(合并单元格产生的相邻重复标题只保留一个)作为csv的表头
+func newCSVRowWriter(w io.Writer, tps eorm.TitlePaths, joiner string) (*csvRowWriter, error) {
+	cw := &csvRowWriter{w: csv.NewWriter(w)}
+	header := make([]string, len(tps))
+	for i, tp := range tps {
+		header[i] = strings.Join(titleWords(tp), joiner)
+	}
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvRowWriter) write(values []string) error { return cw.w.Write(values) }
+
+func (cw *csvRowWriter) flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
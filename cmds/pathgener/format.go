@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/stephenfire/go-eorm"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatCSV  = "csv"
+)
+
+// encode 使用json或yaml格式输出v
+func encode(w io.Writer, format string, v any) error {
+	if strings.ToLower(format) == formatYAML {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeTitleColumns 按format输出表头每一列的信息，text 与 TitlePaths.Info 的格式相同
+func writeTitleColumns(w io.Writer, format string, tcs eorm.TitleColumns) error {
+	switch strings.ToLower(format) {
+	case formatText, "":
+		_, err := fmt.Fprintln(w, tcs.Paths().Info())
+		return err
+	case formatJSON, formatYAML:
+		return encode(w, format, tcs)
+	case formatCSV:
+		return writeTitleColumnsCSV(w, tcs)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// writeTitleColumnsCSV 每一列输出一行，title path的每一级标题及其来源分别输出为 segment<N>、source<N> 列
+func writeTitleColumnsCSV(w io.Writer, tcs eorm.TitleColumns) error {
+	depth := 0
+	for _, tc := range tcs {
+		depth = max(depth, len(tc.Path))
+	}
+	cw := csv.NewWriter(w)
+	header := []string{"index", "letter", "encoded"}
+	for i := 1; i <= depth; i++ {
+		header = append(header, "segment"+strconv.Itoa(i), "source"+strconv.Itoa(i))
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, tc := range tcs {
+		record := []string{strconv.Itoa(tc.Index), tc.Letter, tc.Encoded}
+		for i := 0; i < depth; i++ {
+			if i < len(tc.Path) {
+				record = append(record, tc.Path[i], string(tc.Sources[i]))
+			} else {
+				record = append(record, "", "")
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/stephenfire/go-eorm"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	rowsFlag = &cli.IntFlag{
+		Name:  "rows",
+		Usage: "preview the top `N` rows of each sheet, also used to suggest the title layout",
+		Value: 10,
+	}
+
+	widthFlag = &cli.IntFlag{
+		Name:  "width",
+		Usage: "truncate the preview cells to `N` characters",
+		Value: 16,
+	}
+
+	inspectCommand = &cli.Command{
+		Name:      "inspect",
+		Usage:     "list the sheets of the workbook with their sizes, merged ranges, a preview and the suggested title layout",
+		UsageText: "pathgener --file FILE [--sheet SHEET] inspect [--rows N] [--width N] [--format text|json|yaml]",
+		Flags:     []cli.Flag{rowsFlag, widthFlag, formatFlag},
+		Action:    inspect,
+	}
+)
+
+// sheetInfo inspect 输出的一个sheet的信息，MergedSupport 为false时表示该格式不支持读取合并单元格
+type sheetInfo struct {
+	Index          int              `json:"index" yaml:"index"`
+	Name           string           `json:"name" yaml:"name"`
+	Rows           int              `json:"rows" yaml:"rows"`
+	Columns        int              `json:"columns" yaml:"columns"`
+	Merged         []eorm.CellRange `json:"merged" yaml:"merged"`
+	MergedSupport  bool             `json:"merged_supported" yaml:"merged_supported"`
+	SuggestedStart int              `json:"suggested_start_row" yaml:"suggested_start_row"`
+	SuggestedDepth int              `json:"suggested_depth" yaml:"suggested_depth"`
+	Preview        [][]string       `json:"preview" yaml:"preview"`
+}
+
+func inspect(ctx *cli.Context) error {
+	if err := requireFlags(ctx, fileFlag); err != nil {
+		return err
+	}
+	wb, err := eorm.NewWorkbook(ctx.String(fileFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	sheetname := ctx.String(sheetFlag.Name)
+	var infos []*sheetInfo
+	for i := 0; i < wb.SheetCount(); i++ {
+		sheet, err := eorm.GetSheetContext(ctx.Context, wb, i)
+		if err != nil {
+			return fmt.Errorf("get sheet %d: %w", i, err)
+		}
+		if sheetname != "" && sheet.GetName() != sheetname {
+			continue
+		}
+		info, err := inspectSheet(i, sheet, ctx.Int(rowsFlag.Name))
+		if err != nil {
+			return fmt.Errorf("inspect sheet %q: %w", sheet.GetName(), err)
+		}
+		infos = append(infos, info)
+	}
+	if sheetname != "" && len(infos) == 0 {
+		return fmt.Errorf("sheet %q not found", sheetname)
+	}
+
+	switch format := strings.ToLower(outputFormat(ctx)); format {
+	case formatText, "":
+		return writeSheetInfos(ctx.App.Writer, infos, ctx.Int(widthFlag.Name))
+	case formatJSON, formatYAML:
+		return encode(ctx.App.Writer, format, infos)
+	default:
+		return fmt.Errorf("unsupported format %q for inspect", format)
+	}
+}
+
+func inspectSheet(index int, sheet eorm.Sheet, previewRows int) (*sheetInfo, error) {
+	info := &sheetInfo{Index: index, Name: sheet.GetName(), Rows: sheet.RowCount()}
+	for i := 0; i < sheet.RowCount(); i++ {
+		row, err := sheet.GetRow(i)
+		if err != nil {
+			if errors.Is(err, eorm.ErrRowNotFound) {
+				if i < previewRows {
+					info.Preview = append(info.Preview, nil)
+				}
+				continue
+			}
+			return nil, fmt.Errorf("get row %d: %w", i, err)
+		}
+		info.Columns = max(info.Columns, row.ColumnCount())
+		if i < previewRows {
+			var cols []string
+			for j, v := range row.AllColumns() {
+				for len(cols) <= j {
+					cols = append(cols, "")
+				}
+				cols[j] = v
+			}
+			info.Preview = append(info.Preview, cols)
+		}
+	}
+	merged, err := eorm.GetMergedRanges(sheet)
+	switch {
+	case err == nil:
+		info.Merged, info.MergedSupport = merged, true
+	case !errors.Is(err, errors.ErrUnsupported):
+		return nil, err
+	}
+	info.SuggestedStart, info.SuggestedDepth = eorm.SuggestTitleLayout(sheet, max(previewRows, 10))
+	return info, nil
+}
+
+func writeSheetInfos(w io.Writer, infos []*sheetInfo, width int) error {
+	for k, info := range infos {
+		if k > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Sheet %d: %s\n", info.Index, info.Name)
+		fmt.Fprintf(w, "  Rows: %d, Columns: %d\n", info.Rows, info.Columns)
+		switch {
+		case !info.MergedSupport:
+			fmt.Fprintln(w, "  Merged: unsupported")
+		case len(info.Merged) == 0:
+			fmt.Fprintln(w, "  Merged: none")
+		default:
+			ranges := make([]string, len(info.Merged))
+			for i, m := range info.Merged {
+				ranges[i] = m.String()
+			}
+			fmt.Fprintf(w, "  Merged: %s\n", strings.Join(ranges, " "))
+		}
+		fmt.Fprintf(w, "  Suggested: --start-row %d --depth %d\n", info.SuggestedStart, info.SuggestedDepth)
+		if len(info.Preview) == 0 {
+			continue
+		}
+		columns := 0
+		for _, cols := range info.Preview {
+			columns = max(columns, len(cols))
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', tabwriter.Debug)
+		header := []string{"  "}
+		for j := 0; j < columns; j++ {
+			header = append(header, eorm.ColumnLetter(j))
+		}
+		fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+		for i, cols := range info.Preview {
+			line := []string{fmt.Sprintf("  %d", i+1)}
+			for j := 0; j < columns; j++ {
+				var v string
+				if j < len(cols) {
+					v = previewCell(cols[j], width)
+				}
+				line = append(line, v)
+			}
+			fmt.Fprintln(tw, strings.Join(line, "\t")+"\t")
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// previewCell 换行替换为空格，超过width个字符时截断
+func previewCell(v string, width int) string {
+	v = strings.Join(strings.Fields(v), " ")
+	if width > 0 && utf8.RuneCountInString(v) > width {
+		v = string([]rune(v)[:width-1]) + "…"
+	}
+	return v
+}
@@ -16,10 +16,9 @@ import (
 
 var (
 	fileFlag = &cli.StringFlag{
-		Name:     "file",
-		Usage:    "the input excel `FILE`",
-		Required: true,
-		Aliases:  []string{"f"},
+		Name:    "file",
+		Usage:   "the input excel `FILE`, required",
+		Aliases: []string{"f"},
 	}
 
 	sheetFlag = &cli.StringFlag{
@@ -29,10 +28,9 @@ var (
 	}
 
 	depthFlag = &cli.IntFlag{
-		Name:     "depth",
-		Usage:    "specify the first `DEPTH` rows of excel as the title path",
-		Required: true,
-		Aliases:  []string{"d"},
+		Name:    "depth",
+		Usage:   "specify the first `DEPTH` rows of excel as the title path, required",
+		Aliases: []string{"d"},
 	}
 
 	firstWildcardFlag = &cli.BoolFlag{
@@ -63,6 +61,12 @@ var (
 		Value:   0,
 	}
 
+	formatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "output `FORMAT`: text, json, yaml or csv for title paths; text, json or yaml for check; jsonl or csv for dump",
+		Value: formatText,
+	}
+
 	allFlags = []cli.Flag{
 		fileFlag,
 		sheetFlag,
@@ -71,10 +75,12 @@ var (
 		lastLayerEmptyNotAsMergedFlag,
 		trimSpaceFlag,
 		startRowFlag,
+		formatFlag,
 	}
 )
 
-func main() {
+// newApp 创建命令行应用，输出写入 cli.App.Writer
+func newApp() *cli.App {
 	app := &cli.App{
 		Name:      "pathgener",
 		Usage:     "generate title paths for an excel file",
@@ -82,12 +88,26 @@ func main() {
 		Copyright: eorm.Copyright,
 		Flags:     allFlags,
 		Action:    pathgener,
+		// eorm标签中的constraint以','分隔，不能作为多个值
+		DisableSliceFlagSeparator: true,
+		Commands: []*cli.Command{
+			checkCommand,
+			diffCommand,
+			dumpCommand,
+			inspectCommand,
+			structCommand,
+		},
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 	sort.Sort(cli.FlagsByName(app.Flags))
 	for _, cmd := range app.Commands {
 		sort.Sort(cli.FlagsByName(cmd.Flags))
 	}
+	return app
+}
+
+func main() {
+	app := newApp()
 	var canceled atomic.Bool
 	baseCtx, cancel := context.WithCancel(context.Background())
 	go func() {
@@ -101,31 +121,58 @@ func main() {
 		ss := <-sigs
 		log.Warnf("GOT A SYSTEM SIGNAL[%s]\n", ss.String())
 	}()
+	// cli.ExitCoder(如diff发现不兼容的修改)在 RunContext 中以其退出码结束进程，其他错误以1结束
 	if err := app.RunContext(baseCtx, os.Args); err != nil {
 		log.Errorf("exit from main: %v", err)
 		if canceled.CompareAndSwap(false, true) {
 			cancel()
 		}
+		os.Exit(1)
 	}
 }
 
-func pathgener(ctx *cli.Context) error {
-	filename := ctx.String(fileFlag.Name)
-	sheetname := ctx.String(sheetFlag.Name)
-	depth := ctx.Int(depthFlag.Name)
-	wb, err := eorm.NewWorkbook(filename)
+// requireFlags 子命令与根命令使用相同的参数，所以不能通过 cli.Flag 的 Required 检查，由各命令自行检查
+func requireFlags(ctx *cli.Context, flags ...cli.Flag) error {
+	for _, flag := range flags {
+		name := flag.Names()[0]
+		if !ctx.IsSet(name) {
+			return fmt.Errorf("required flag %q not set", name)
+		}
+	}
+	return nil
+}
+
+// outputFormat formatFlag 可以在根命令或子命令中指定，子命令中的优先
+func outputFormat(ctx *cli.Context) string {
+	for _, c := range ctx.Lineage() {
+		if c.IsSet(formatFlag.Name) {
+			return c.String(formatFlag.Name)
+		}
+	}
+	return formatText
+}
+
+// openSheet 打开 fileFlag 指定的文件，返回 sheetFlag 指定的sheet，未指定时返回第一个sheet
+func openSheet(ctx *cli.Context) (eorm.Workbook, eorm.Sheet, error) {
+	wb, err := eorm.NewWorkbook(ctx.String(fileFlag.Name))
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	var sheet eorm.Sheet
-	if sheetname != "" {
+	if sheetname := ctx.String(sheetFlag.Name); sheetname != "" {
 		sheet, err = wb.GetSheetByName(sheetname)
 	} else {
 		sheet, err = wb.GetSheet(0)
 	}
 	if err != nil {
-		return err
+		_ = wb.Close()
+		return nil, nil, err
 	}
+	return wb, sheet, nil
+}
+
+// titleOptions 根据命令行参数生成分析表头的 eorm.Option
+func titleOptions(ctx *cli.Context) []eorm.Option {
 	var opts []eorm.Option
 	if ctx.Bool(firstWildcardFlag.Name) {
 		opts = append(opts, eorm.WithFirstRowWildcard())
@@ -139,10 +186,23 @@ func pathgener(ctx *cli.Context) error {
 	if startRow := ctx.Int(startRowFlag.Name); startRow > 0 {
 		opts = append(opts, eorm.WithTitleStartRow(startRow))
 	}
-	tps, err := eorm.BuildTitlePaths(sheet, depth, opts...)
+	return opts
+}
+
+func pathgener(ctx *cli.Context) error {
+	if err := requireFlags(ctx, fileFlag, depthFlag); err != nil {
+		return err
+	}
+	wb, sheet, err := openSheet(ctx)
 	if err != nil {
 		return err
 	}
-	fmt.Println(tps.Info())
-	return nil
+	defer func() {
+		_ = wb.Close()
+	}()
+	tcs, err := eorm.BuildTitleColumns(sheet, ctx.Int(depthFlag.Name), titleOptions(ctx)...)
+	if err != nil {
+		return err
+	}
+	return writeTitleColumns(ctx.App.Writer, outputFormat(ctx), tcs)
 }
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stephenfire/go-eorm"
+	"github.com/urfave/cli/v2"
+)
+
+var titleFiles = []string{"title.xlsx", "title.ods", "title.csv"}
+
+// checkObj 用于 check --type，与 eorm.CompileMapperSchema 一样使用属性的类型及Set方法
+type checkObj struct {
+	Id    int64   `eorm:"序号//"`
+	Name  string  `eorm:"名称//,required"`
+	Level []int64 `eorm:"第一级/第二级/第三级"`
+	Skip  string
+}
+
+// runMainEnv 设置时测试进程以其值(JSON数组)为参数作为 pathgener 运行，用于检查退出码
+const runMainEnv = "PATHGENER_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) != "" {
+		var args []string
+		if err := json.Unmarshal([]byte(os.Getenv(runMainEnv)), &args); err != nil {
+			panic(err)
+		}
+		os.Args = append([]string{"pathgener"}, args...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func testFile(name string) string {
+	return filepath.Join("..", "..", "testdata", name)
+}
+
+// runApp 运行 pathgener，返回标准输出。cli.Exit 返回的错误不会结束测试进程
+func runApp(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	app := newApp()
+	out := new(bytes.Buffer)
+	app.Writer = out
+	app.ErrWriter = io.Discard
+	app.ExitErrHandler = func(*cli.Context, error) {}
+	err := app.Run(append([]string{"pathgener"}, args...))
+	return out.String(), err
+}
+
+func TestTitleColumns(t *testing.T) {
+	for _, file := range titleFiles {
+		out, err := runApp(t, "--file", testFile(file), "--depth", "3")
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if !strings.Contains(out, "   7: [第一级/第二级/第三级]\n   8: [第一级/第二级/第三级]") {
+			t.Fatalf("%s: unexpected output:\n%s", file, out)
+		}
+
+		out, err = runApp(t, "--file", testFile(file), "--depth", "3", "--format", "json")
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		var tcs eorm.TitleColumns
+		if err = json.Unmarshal([]byte(out), &tcs); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if len(tcs) != 8 || tcs[3].Encoded != "第一级/反引号%60测试/斜杠%2F" ||
+			!reflect.DeepEqual(tcs[3].Sources, []eorm.SegmentSource{eorm.SourceMerged, eorm.SourceMerged, eorm.SourceCell}) {
+			t.Fatalf("%s: unexpected title columns: %+v", file, tcs)
+		}
+
+		out, err = runApp(t, "--file", testFile(file), "--depth", "3", "--format", "csv")
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if len(records) != 9 || strings.Join(records[0], ",") != "index,letter,encoded,segment1,source1,segment2,source2,segment3,source3" ||
+			strings.Join(records[2], ",") != "1,B,名称//,名称,cell,,merged,,merged" {
+			t.Fatalf("%s: unexpected csv: %q", file, records)
+		}
+	}
+}
+
+func TestDump(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			// 重复的title path输出为数组
+			name: "jsonl",
+			args: []string{"dump"},
+			want: `{"序号//":"10","名称//":"name10","第一级/反引号%60测试/空%20格":"TRUE","第一级/反引号%60测试/斜杠%2F":"13",` +
+				`"第一级/双引号%22测试/反斜杠%5C":"14","第一级/双引号%22测试/第三级":"15","第一级/第二级/第三级":["16","17"]}` + "\n" +
+				`{"序号//":"20","名称//":"name20","第一级/反引号%60测试/空%20格":"FALSE","第一级/反引号%60测试/斜杠%2F":"23",` +
+				`"第一级/双引号%22测试/反斜杠%5C":"24","第一级/双引号%22测试/第三级":"25","第一级/第二级/第三级":["26","27"]}` + "\n",
+		},
+		{
+			// 表头由非空标题连接，合并单元格产生的空标题被忽略
+			name: "csv",
+			args: []string{"dump", "--format", "csv", "--joiner", " > "},
+			want: `序号,名称,第一级 > 反引号` + "`" + `测试 > 空 格,第一级 > 反引号` + "`" + `测试 > 斜杠/,` +
+				`"第一级 > 双引号""测试 > 反斜杠\","第一级 > 双引号""测试 > 第三级",第一级 > 第二级 > 第三级,第一级 > 第二级 > 第三级` + "\n" +
+				"10,name10,TRUE,13,14,15,16,17\n" +
+				"20,name20,FALSE,23,24,25,26,27\n",
+		},
+	}
+	for _, file := range titleFiles {
+		for _, tt := range tests {
+			out, err := runApp(t, append([]string{"--file", testFile(file), "--depth", "3"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", file, tt.name, err)
+			}
+			if out != tt.want {
+				t.Fatalf("%s/%s: expecting:\n%s\ngot:\n%s", file, tt.name, tt.want, out)
+			}
+		}
+	}
+	if _, err := runApp(t, "--file", testFile("title.csv"), "--depth", "3", "dump", "--format", "yaml"); err == nil {
+		t.Fatal("unsupported format error expected")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var report struct {
+		Fields []struct {
+			Name    string `json:"name"`
+			Columns []int  `json:"columns"`
+		} `json:"fields"`
+		UnmatchedFields []string `json:"unmatched_fields"`
+		UnmappedColumns []struct {
+			Index int `json:"index"`
+		} `json:"unmapped_columns"`
+		PerfectMatch bool `json:"perfect_match"`
+		Matched      bool `json:"matched"`
+	}
+	for _, file := range titleFiles {
+		out, err := runApp(t, "--file", testFile(file), "check", "--format", "json",
+			"--tag", "序号//", "--tag", "名称//,required", "--tag", "第一级/第二级/第三级", "--tag", "缺少//")
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if err = json.Unmarshal([]byte(out), &report); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if len(report.Fields) != 4 || !reflect.DeepEqual(report.Fields[2].Columns, []int{6, 7}) ||
+			report.Fields[3].Columns != nil || !reflect.DeepEqual(report.UnmatchedFields, []string{"缺少//"}) ||
+			len(report.UnmappedColumns) != 4 || report.UnmappedColumns[0].Index != 2 ||
+			report.PerfectMatch || !report.Matched {
+			t.Fatalf("%s: unexpected report:\n%s", file, out)
+		}
+	}
+	if _, err := runApp(t, "--file", testFile("title.csv"), "check"); err == nil {
+		t.Fatal("missing --tag/--type error expected")
+	}
+
+	out, err := runApp(t, "--file", testFile("title.csv"), "check", "--format", "json", "--type", "checkObj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Fields) != 3 || report.Fields[1].Name != "Name" || !reflect.DeepEqual(report.Fields[2].Columns, []int{6, 7}) {
+		t.Fatalf("unexpected report:\n%s", out)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	for _, file := range titleFiles {
+		out, err := runApp(t, "--file", testFile(file), "inspect", "--rows", "2", "--format", "json")
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		var infos []*sheetInfo
+		if err = json.Unmarshal([]byte(out), &infos); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if len(infos) == 0 {
+			t.Fatalf("%s: no sheet", file)
+		}
+		info := infos[0]
+		if info.Rows != 5 || info.Columns != 8 || info.SuggestedStart != 0 || info.SuggestedDepth != 3 ||
+			len(info.Preview) != 2 || info.Preview[0][0] != "序号" {
+			t.Fatalf("%s: unexpected sheet info: %+v", file, info)
+		}
+
+		out, err = runApp(t, "--file", testFile(file), "inspect", "--rows", "2")
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if !strings.Contains(out, "Suggested: --start-row 0 --depth 3") {
+			t.Fatalf("%s: unexpected output:\n%s", file, out)
+		}
+	}
+	if _, err := runApp(t, "--file", testFile("title.csv"), "--sheet", "none", "inspect"); err == nil {
+		t.Fatal("sheet not found error expected")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, file := range titleFiles[1:] {
+		out, err := runApp(t, "--depth", "3", "diff", testFile(titleFiles[0]), testFile(file))
+		if err != nil || out != "no changes\n" {
+			t.Fatalf("%s: no changes expected, got %q %v", file, out, err)
+		}
+	}
+
+	out, err := runApp(t, "--depth", "2", "diff", testFile("title.xlsx"), testFile("title_start_at_2.xlsx"))
+	var exitErr cli.ExitCoder
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("exit code 2 expected, got %v", err)
+	}
+	if !strings.Contains(out, "~ renamed   A(1)     [序号/] -> [/]") || !strings.HasSuffix(out, "breaking: true\n") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	out, err = runApp(t, "--depth", "3", "diff", "--format", "json", testFile("title.xlsx"), testFile("title.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d eorm.TitleDiff
+	if err = json.Unmarshal([]byte(out), &d); err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsEmpty() {
+		t.Fatalf("no changes expected, got %s", out)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"no changes", []string{"--depth", "3", "diff", testFile("title.xlsx"), testFile("title.csv")}, 0},
+		{"breaking", []string{"--depth", "2", "diff", testFile("title.xlsx"), testFile("title_start_at_2.xlsx")}, 2},
+		{"error", []string{"--depth", "3", "diff", testFile("not_exist.xlsx")}, 1},
+		{"missing flag", []string{"--file", testFile("title.csv")}, 1},
+	}
+	for _, tt := range tests {
+		cmd := exec.Command(os.Args[0], "-test.run=^$")
+		args, _ := json.Marshal(tt.args)
+		cmd.Env = append(os.Environ(), runMainEnv+"="+string(args))
+		cmd.Stdout, cmd.Stderr = io.Discard, io.Discard
+		err := cmd.Run()
+		code := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if code != tt.code {
+			t.Fatalf("%s: expecting exit code %d, got %d", tt.name, tt.code, code)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mozillazg/go-pinyin"
+	"github.com/stephenfire/go-eorm"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	nameFlag = &cli.StringFlag{
+		Name:    "name",
+		Usage:   "the generated struct type `NAME`",
+		Value:   "Row",
+		Aliases: []string{"n"},
+	}
+
+	packageFlag = &cli.StringFlag{
+		Name:    "package",
+		Usage:   "emit a complete go file with package clause and imports in `PACKAGE`",
+		Aliases: []string{"p"},
+	}
+
+	sampleFlag = &cli.IntFlag{
+		Name:  "sample",
+		Usage: "infer field types by sampling the first `N` data rows, 0 means all strings",
+		Value: 100,
+	}
+
+	outputFlag = &cli.StringFlag{
+		Name:    "output",
+		Usage:   "write to `FILE` instead of stdout",
+		Aliases: []string{"o"},
+	}
+
+	structCommand = &cli.Command{
+		Name:      "struct",
+		Usage:     "generate a go struct with eorm tags from the title of the sheet",
+		UsageText: "pathgener --file FILE --depth DEPTH [OPTIONS] struct [--name NAME] [--package PACKAGE] [--sample N] [--output FILE]",
+		Flags:     []cli.Flag{nameFlag, packageFlag, sampleFlag, outputFlag},
+		Action:    genStruct,
+	}
+
+	// timeLayouts 推断时间类型时依次尝试的格式，一列中所有的非空值都能用同一个格式解析时才认为是时间
+	timeLayouts = []string{
+		time.DateTime,
+		time.DateOnly,
+		time.RFC3339,
+		"2006/01/02 15:04:05",
+		"2006/1/2 15:04",
+		"2006/1/2",
+		"1/2/06 15:04",
+		"01-02-06",
+		"1/2/06",
+		"2006年1月2日",
+	}
+)
+
+type (
+	// fieldKind 推断出的列类型
+	fieldKind int
+
+	// columnStats 采样数据行时记录一列的值能够被哪些类型解析
+	columnStats struct {
+		nonEmpty   int
+		notInt     bool
+		notFloat   bool
+		notBool    bool
+		timeLayout []string // 仍然能够解析所有非空值的时间格式
+	}
+
+	// structField 一个生成的属性，重复的title path对应多列，生成slice属性
+	structField struct {
+		path    eorm.TitlePath
+		columns []int
+		name    string
+		kind    fieldKind
+		layout  string
+	}
+)
+
+const (
+	kindString fieldKind = iota
+	kindInt64
+	kindFloat64
+	kindBool
+	kindTime
+)
+
+func (k fieldKind) goType() string {
+	switch k {
+	case kindInt64:
+		return "int64"
+	case kindFloat64:
+		return "float64"
+	case kindBool:
+		return "bool"
+	case kindTime:
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
+
+func newColumnStats() *columnStats {
+	return &columnStats{timeLayout: timeLayouts}
+}
+
+func (s *columnStats) add(row eorm.Row, index int) {
+	v, err := row.GetColumn(index)
+	if err != nil || strings.TrimSpace(v) == "" {
+		return
+	}
+	s.nonEmpty++
+	if !s.notInt {
+		_, err = row.GetInt64Column(index)
+		s.notInt = err != nil
+	}
+	if !s.notFloat {
+		_, err = row.GetFloat64Column(index)
+		s.notFloat = err != nil
+	}
+	if !s.notBool {
+		_, err = row.GetBoolColumn(index)
+		s.notBool = err != nil
+	}
+	var layouts []string
+	for _, layout := range s.timeLayout {
+		if _, err = time.Parse(layout, strings.TrimSpace(v)); err == nil {
+			layouts = append(layouts, layout)
+		}
+	}
+	s.timeLayout = layouts
+}
+
+// kind 没有非空值的列使用string
+func (s *columnStats) kind() (fieldKind, string) {
+	switch {
+	case s.nonEmpty == 0:
+		return kindString, ""
+	case !s.notInt:
+		return kindInt64, ""
+	case !s.notFloat:
+		return kindFloat64, ""
+	case !s.notBool:
+		return kindBool, ""
+	case len(s.timeLayout) > 0:
+		return kindTime, s.timeLayout[0]
+	default:
+		return kindString, ""
+	}
+}
+
+func (s *columnStats) merge(o *columnStats) {
+	s.nonEmpty += o.nonEmpty
+	s.notInt = s.notInt || o.notInt
+	s.notFloat = s.notFloat || o.notFloat
+	s.notBool = s.notBool || o.notBool
+	var layouts []string
+	for _, layout := range s.timeLayout {
+		for _, ol := range o.timeLayout {
+			if layout == ol {
+				layouts = append(layouts, layout)
+				break
+			}
+		}
+	}
+	s.timeLayout = layouts
+}
+
+func genStruct(ctx *cli.Context) error {
+	if err := requireFlags(ctx, fileFlag, depthFlag); err != nil {
+		return err
+	}
+	typeName := ctx.String(nameFlag.Name)
+	if !token.IsIdentifier(typeName) || !token.IsExported(typeName) {
+		return fmt.Errorf("invalid struct name %q", typeName)
+	}
+	wb, sheet, err := openSheet(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	depth := ctx.Int(depthFlag.Name)
+	opts := titleOptions(ctx)
+	tps, err := eorm.BuildTitlePaths(sheet, depth, opts...)
+	if err != nil {
+		return err
+	}
+	stats, err := sampleColumns(sheet, len(tps), eorm.NewParams(opts...).MinRows(depth), ctx.Int(sampleFlag.Name))
+	if err != nil {
+		return err
+	}
+	src, err := generateStruct(typeName, ctx.String(packageFlag.Name), tps, stats)
+	if err != nil {
+		return err
+	}
+	if output := ctx.String(outputFlag.Name); output != "" {
+		return os.WriteFile(output, src, 0o644)
+	}
+	_, err = ctx.App.Writer.Write(src)
+	return err
+}
+
+// sampleColumns 读取由startRow开始的n个数据行(跳过不存在的行)，统计每一列的值
+func sampleColumns(sheet eorm.Sheet, columns, startRow, n int) ([]*columnStats, error) {
+	stats := make([]*columnStats, columns)
+	for i := range stats {
+		stats[i] = newColumnStats()
+	}
+	for i := startRow; i < sheet.RowCount() && i < startRow+n; i++ {
+		row, err := sheet.GetRow(i)
+		if err != nil {
+			if errors.Is(err, eorm.ErrRowNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("get row %d: %w", i, err)
+		}
+		for j := 0; j < columns && j < row.ColumnCount(); j++ {
+			stats[j].add(row, j)
+		}
+	}
+	return stats, nil
+}
+
+// buildFields 相同title path的列合并为一个属性，属性按第一列的顺序排列
+func buildFields(tps eorm.TitlePaths, stats []*columnStats) []*structField {
+	var fields []*structField
+	byPath := make(map[string]*structField)
+	merged := make(map[*structField]*columnStats)
+	for i, tp := range tps {
+		key := tp.Encode()
+		if f, ok := byPath[key]; ok {
+			f.columns = append(f.columns, i)
+			merged[f].merge(stats[i])
+			continue
+		}
+		f := &structField{path: tp, columns: []int{i}}
+		st := newColumnStats()
+		st.merge(stats[i])
+		merged[f] = st
+		byPath[key] = f
+		fields = append(fields, f)
+	}
+	for _, f := range fields {
+		f.kind, f.layout = merged[f].kind()
+	}
+	nameFields(fields)
+	return fields
+}
+
+// nameFields 属性名优先由title path的最后一级生成，重名时逐级加入上一级标题，仍然重名时增加序号
+func nameFields(fields []*structField) {
+	levels := make([]int, len(fields))
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		levels[i] = 1
+		names[i] = fieldName(f.path, 1, f.columns[0])
+	}
+	for changed := true; changed; {
+		changed = false
+		counts := make(map[string]int)
+		for _, name := range names {
+			counts[name]++
+		}
+		for i, f := range fields {
+			if counts[names[i]] > 1 && levels[i] < len(titleWords(f.path)) {
+				levels[i]++
+				names[i] = fieldName(f.path, levels[i], f.columns[0])
+				changed = true
+			}
+		}
+	}
+	used := make(map[string]bool)
+	for i, f := range fields {
+		name := names[i]
+		for n := 2; used[name]; n++ {
+			name = names[i] + strconv.Itoa(n)
+		}
+		used[name] = true
+		f.name = name
+	}
+}
+
+// titleWords title path中非空且与上一级不同的标题，合并单元格会使相邻的标题相同
+func titleWords(tp eorm.TitlePath) []string {
+	var words []string
+	for _, title := range tp {
+		title = strings.TrimSpace(title)
+		if title == "" || (len(words) > 0 && words[len(words)-1] == title) {
+			continue
+		}
+		words = append(words, title)
+	}
+	return words
+}
+
+// fieldName 使用title path的最后levels级标题生成导出的属性名，无法生成时使用列名
+func fieldName(tp eorm.TitlePath, levels, column int) string {
+	words := titleWords(tp)
+	if len(words) > levels {
+		words = words[len(words)-levels:]
+	}
+	sb := strings.Builder{}
+	for _, word := range words {
+		sb.WriteString(identifier(word))
+	}
+	name := sb.String()
+	if name == "" {
+		return "Column" + eorm.ColumnLetter(column)
+	}
+	if !unicode.IsLetter(rune(name[0])) {
+		name = "F" + name
+	}
+	return name
+}
+
+var pinyinArgs = pinyin.NewArgs()
+
+// identifier 将标题转换为首字母大写的ASCII标识符：汉字转为拼音，字母数字保留，其他字符作为单词分隔，
+// 无法转换的字符使用其Unicode编码
+func identifier(title string) string {
+	sb := strings.Builder{}
+	upper := true
+	for _, r := range title {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			sb.WriteRune(r)
+			upper = false
+		case unicode.Is(unicode.Han, r):
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 && py[0] != "" {
+				sb.WriteString(strings.ToUpper(py[0][:1]) + py[0][1:])
+			} else {
+				sb.WriteString(fmt.Sprintf("U%04X", r))
+			}
+			upper = true
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteString(fmt.Sprintf("U%04X", r))
+			upper = true
+		default:
+			upper = true
+		}
+	}
+	return sb.String()
+}
+
+func generateStruct(typeName, pkg string, tps eorm.TitlePaths, stats []*columnStats) ([]byte, error) {
+	fields := buildFields(tps, stats)
+	hasTime := false
+	for _, f := range fields {
+		hasTime = hasTime || f.kind == kindTime
+	}
+	var buf bytes.Buffer
+	if pkg != "" {
+		fmt.Fprintf(&buf, "package %s\n\n", pkg)
+		if hasTime {
+			buf.WriteString("import (\n\"strings\"\n\"time\"\n)\n\n")
+		}
+	}
+	fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+	for _, f := range fields {
+		typ := f.kind.goType()
+		if len(f.columns) > 1 {
+			typ = "[]" + typ
+		}
+		fmt.Fprintf(&buf, "%s %s `eorm:%s`", f.name, typ, strconv.Quote(f.path.Encode()))
+		cols := make([]string, len(f.columns))
+		for i, c := range f.columns {
+			cols[i] = eorm.ColumnLetter(c)
+		}
+		fmt.Fprintf(&buf, " // %s\n", strings.Join(cols, ","))
+	}
+	buf.WriteString("}\n")
+	// 类型名可能以非ASCII字符开头(如 Ärger)，按rune取首字符
+	first, _ := utf8.DecodeRuneInString(typeName)
+	receiver := string(unicode.ToLower(first))
+	for _, f := range fields {
+		if f.kind != kindTime {
+			continue
+		}
+		// eorm 不能直接转换时间类型，生成解析字符串的 Setter
+		if len(f.columns) > 1 {
+			fmt.Fprintf(&buf, "\n// Set%s 按 %q 解析时间，无法解析的值为零值\n", f.name, f.layout)
+			fmt.Fprintf(&buf, "func (%s *%s) Set%s(values []string) {\n", receiver, typeName, f.name)
+			fmt.Fprintf(&buf, "%s.%s = make([]time.Time, len(values))\n", receiver, f.name)
+			buf.WriteString("for idx, value := range values {\n")
+			fmt.Fprintf(&buf, "%s.%s[idx], _ = time.Parse(%q, strings.TrimSpace(value))\n", receiver, f.name, f.layout)
+			buf.WriteString("}\n}\n")
+		} else {
+			fmt.Fprintf(&buf, "\n// Set%s 按 %q 解析时间，无法解析的值为零值\n", f.name, f.layout)
+			fmt.Fprintf(&buf, "func (%s *%s) Set%s(value string) {\n", receiver, typeName, f.name)
+			fmt.Fprintf(&buf, "%s.%s, _ = time.Parse(%q, strings.TrimSpace(value))\n", receiver, f.name, f.layout)
+			buf.WriteString("}\n")
+		}
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w", err)
+	}
+	return src, nil
+}
@@ -0,0 +1,62 @@
+package eorm
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// ColumnString 读取row中index列的字符串值，Params.TrimSpace 为true时删除首尾空格。
+// ColumnString/ColumnInt64/ColumnFloat64/ColumnBool 是反射方式和 RowDecoder 共同使用的转换规则：
+//
+// * 单元格为空(ErrEmptyCell)时返回零值，但 constraint 为 ConstraintNotNull 时返回 ErrEmptyCell
+// * constraint 为 ConstraintNotNull 时，读取出错或值为零值均返回 ErrEmptyCell
+// * Params.IgnoreParseError 为true时 ErrParseError 作为零值处理，Params.IgnoreOutOfRange 为true时 ErrOutOfRange 作为零值处理
+func ColumnString(row Row, index int, constraint Constraint, params *Params) (string, error) {
+	return columnValue(func(index int) (string, error) {
+		v, err := row.GetColumn(index)
+		if err != nil {
+			return v, err
+		}
+		if params.TrimSpace {
+			return strings.TrimSpace(v), nil
+		}
+		return v, nil
+	}, index, constraint, params, func(v string) bool { return v == "" })
+}
+
+// ColumnInt64 读取row中index列的int64值，规则见 ColumnString
+func ColumnInt64(row Row, index int, constraint Constraint, params *Params) (int64, error) {
+	return columnValue(row.GetInt64Column, index, constraint, params, func(v int64) bool { return v == 0 })
+}
+
+// ColumnFloat64 读取row中index列的float64值，规则见 ColumnString
+func ColumnFloat64(row Row, index int, constraint Constraint, params *Params) (float64, error) {
+	// 与 reflect.Value.IsZero 一致，-0.0 不是零值
+	return columnValue(row.GetFloat64Column, index, constraint, params, func(v float64) bool { return math.Float64bits(v) == 0 })
+}
+
+// ColumnBool 读取row中index列的bool值，规则见 ColumnString
+func ColumnBool(row Row, index int, constraint Constraint, params *Params) (bool, error) {
+	return columnValue(row.GetBoolColumn, index, constraint, params, func(v bool) bool { return !v })
+}
+
+func columnValue[V any](get func(index int) (V, error), index int, constraint Constraint, params *Params,
+	isZero func(V) bool) (V, error) {
+	var zero V
+	v, err := get(index)
+	if err != nil && !constraint.NeedValue() && errors.Is(err, ErrEmptyCell) {
+		v, err = zero, nil
+	}
+	if constraint.NeedValue() && (err != nil || isZero(v)) {
+		return zero, ErrEmptyCell
+	}
+	if err != nil {
+		if (params.IgnoreParseError && errors.Is(err, ErrParseError)) ||
+			(params.IgnoreOutOfRange && errors.Is(err, ErrOutOfRange)) {
+			return zero, nil
+		}
+		return zero, err
+	}
+	return v, nil
+}
@@ -0,0 +1,172 @@
+package eorm
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+const defaultCsvSheetName = "Sheet1"
+
+type (
+	// csvWorkbook csv/tsv 文件只包含一个sheet，打开时即读取全部内容
+	csvWorkbook struct {
+		sheet *textSheet
+	}
+)
+
+// NewCsvWorkbook 打开csv/tsv文件。未指定分隔符时，.tsv文件使用'\t'，其他文件使用','。
+// 未指定sheet名称时，使用不含扩展名的文件名作为唯一sheet的名称。
+func NewCsvWorkbook(filePath string, opts ...WorkbookOption) (Workbook, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("excel/csv: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return newCsvWorkbook(f, csvFileParams(filePath, opts...))
+}
+
+// csvFileParams 根据文件名补充缺省的sheet名称和分隔符
+func csvFileParams(filename string, opts ...WorkbookOption) *WorkbookParams {
+	params := NewWorkbookParams(opts...)
+	base := filepath.Base(filename)
+	ext := filepath.Ext(base)
+	if params.SheetName == "" {
+		params.SheetName = strings.TrimSuffix(base, ext)
+	}
+	if params.Comma == 0 {
+		switch strings.ToLower(ext) {
+		case ".tsv":
+			params.Comma = '\t'
+		case ".csv":
+			params.Comma = ','
+		}
+	}
+	return params
+}
+
+// NewCsvWorkbookByReader 从reader中读取csv/tsv内容，未指定分隔符时使用','
+func NewCsvWorkbookByReader(reader io.Reader, opts ...WorkbookOption) (Workbook, error) {
+	return newCsvWorkbook(reader, NewWorkbookParams(opts...))
+}
+
+func newCsvWorkbook(reader io.Reader, params *WorkbookParams) (*csvWorkbook, error) {
+	name := params.SheetName
+	if name == "" {
+		name = defaultCsvSheetName
+	}
+	comma := params.Comma
+	if comma == 0 {
+		comma = ','
+	}
+	enc := params.Encoding
+	if enc == nil {
+		enc = unicode.UTF8
+	}
+	// 存在BOM时，以BOM指定的编码为准，并去掉BOM
+	decoded := transform.NewReader(reader, unicode.BOMOverride(enc.NewDecoder()))
+
+	lm := params.startLimits()
+	var rows [][]string
+	var err error
+	if params.Quoting == CsvQuoteNone {
+		rows, err = readCsvNoQuote(decoded, comma, params.Comment, lm)
+	} else {
+		r := csv.NewReader(decoded)
+		r.Comma = comma
+		r.Comment = params.Comment
+		r.LazyQuotes = params.Quoting == CsvQuoteLazy
+		r.FieldsPerRecord = -1
+		rows, err = readCsvRecords(r.Read, lm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("excel/csv: %w", err)
+	}
+	return &csvWorkbook{sheet: &textSheet{format: "csv", name: name, allRows: rows}}, nil
+}
+
+// readCsvRecords 逐行读取，并检查资源限制
+func readCsvRecords(read func() ([]string, error), lm *limiter) ([][]string, error) {
+	var rows [][]string
+	for {
+		record, err := read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return rows, nil
+			}
+			return nil, err
+		}
+		if err = lm.checkDeadline(); err != nil {
+			return nil, err
+		}
+		if err = lm.checkRows(len(rows) + 1); err != nil {
+			return nil, err
+		}
+		if err = lm.checkStrings(record); err != nil {
+			return nil, fmt.Errorf("row %d: %w", len(rows), err)
+		}
+		rows = append(rows, record)
+	}
+}
+
+// readCsvNoQuote 按行读取，每行直接以分隔符切分，与 csv.Reader 一致，跳过空行
+func readCsvNoQuote(reader io.Reader, comma, comment rune, lm *limiter) ([][]string, error) {
+	sc := bufio.NewScanner(reader)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	sep := string(comma)
+	return readCsvRecords(func() ([]string, error) {
+		for sc.Scan() {
+			line := strings.TrimSuffix(sc.Text(), "\r")
+			if line == "" {
+				continue
+			}
+			if comment != 0 && strings.HasPrefix(line, string(comment)) {
+				continue
+			}
+			return strings.Split(line, sep), nil
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}, lm)
+}
+
+func (c *csvWorkbook) SheetCount() int {
+	return 1
+}
+
+func (c *csvWorkbook) GetSheet(index int) (Sheet, error) {
+	if index != 0 {
+		return nil, ErrOutOfRange
+	}
+	return c.sheet, nil
+}
+
+func (c *csvWorkbook) GetSheetByName(name string) (Sheet, error) {
+	if name != c.sheet.name {
+		return nil, ErrNotFound
+	}
+	return c.sheet, nil
+}
+
+func (c *csvWorkbook) IterateSheet(index int) (RowIterator, error) {
+	if index != 0 {
+		return nil, ErrOutOfRange
+	}
+	return &textRowIterator{curRow: -1, sheet: c.sheet}, nil
+}
+
+func (c *csvWorkbook) Close() error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package eorm
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestCsvTitle(t *testing.T) {
+	wb, err := NewWorkbook(filepath.Join("testdata", "title.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	sheet, err := wb.GetSheetByName("title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj1{}))
+	if err != nil {
+		t.Fatalf("NewEORM failed: %v", err)
+	}
+	testTitle1(em, t)
+	iterateTest(t, wb)
+}
+
+func TestTsvGBK(t *testing.T) {
+	// 文件中存在不规范的引号，且以GBK编码
+	wb, err := NewWorkbook(filepath.Join("testdata", "title_gbk.tsv"),
+		WithCsvEncoding(simplifiedchinese.GBK), WithCsvQuoting(CsvQuoteNone))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj1{}), WithMatchLevel(MatchLevelPerfect))
+	if err != nil {
+		t.Fatalf("NewEORM failed: %v", err)
+	}
+	testTitle1(em, t)
+
+	_, err = NewWorkbook(filepath.Join("testdata", "title_gbk.tsv"), WithCsvEncoding(simplifiedchinese.GBK))
+	if err == nil {
+		t.Fatal("bare quote error expected")
+	}
+}
+
+func TestCsvUTF16BOM(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join("testdata", "title.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 将内容转换为带BOM的UTF-16LE，未指定编码时应根据BOM识别
+	src = bytes.ReplaceAll(src, []byte(","), []byte(";"))
+	encoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wb, err := NewWorkbookByReadSeeker("upload.csv", bytes.NewReader(encoded), WithCsvComma(';'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = wb.GetSheet(1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("ErrOutOfRange expected, got %v", err)
+	}
+	sheet, err := wb.GetSheetByName("upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj1{}))
+	if err != nil {
+		t.Fatalf("NewEORM failed: %v", err)
+	}
+	testTitle1(em, t)
+}
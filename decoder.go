@@ -0,0 +1,84 @@
+package eorm
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// RowDecoder 不使用反射把Row转换为*T，通常由 cmds/eormgen 生成，并在生成代码的init()中通过 RegisterDecoder 注册。
+// columns 以属性下标(reflect.StructField.Index)为下标，保存该属性在sheet中对应的列(已排序)，未匹配的属性为nil。
+// 每一列的值需要通过 ColumnString 等函数读取，以保证与反射方式的结果一致。RowDecoder 需要支持并发调用。
+// Fingerprint 返回生成decoder时各属性的指纹(见 Fingerprint)，与 MapperSchema.Fingerprint 不一致说明decoder已过期
+type RowDecoder[T any] interface {
+	DecodeRow(row Row, columns [][]int, params *Params) (*T, error)
+	Fingerprint() string
+}
+
+// FieldSignature 参与指纹计算的属性信息
+type FieldSignature struct {
+	Index   int         // 属性下标
+	Name    string      // 属性名
+	Tag     string      // eorm标签
+	Mapping MappingType // 映射方式
+	Setter  string      // Set方法名，直接赋值时为空
+}
+
+// Fingerprint 按顺序计算所有属性的指纹，cmds/eormgen 与 CompileMapperSchema 使用同样的方式计算
+func Fingerprint(fields []FieldSignature) string {
+	h := fnv.New64a()
+	for _, f := range fields {
+		_, _ = fmt.Fprintf(h, "%d\x00%s\x00%s\x00%d\x00%s\n", f.Index, f.Name, f.Tag, f.Mapping, f.Setter)
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// rowDecoders reflect.Type -> *registeredDecoder
+var rowDecoders sync.Map
+
+// registeredDecoder 通过 RegisterDecoder 注册的 RowDecoder[T]，decodeValue 供对象类型在运行时才确定的场景(如 DecodeWorkbook)使用
+type registeredDecoder struct {
+	decoder     any // RowDecoder[T]
+	decodeValue func(row Row, columns [][]int, params *Params) (reflect.Value, error)
+}
+
+// RegisterDecoder 注册类型T的 RowDecoder，之后创建的 RowMapper[T] 使用该decoder转换行(除非设置了 Params.UseReflection)。
+// decoder的指纹与T的 MapperSchema 不一致时(结构体修改后没有重新生成)返回 ErrDecoderMismatch 且不注册，T继续使用反射
+func RegisterDecoder[T any](decoder RowDecoder[T]) error {
+	typ := reflect.TypeFor[T]()
+	schema, err := CompileMapperSchema(typ)
+	if err != nil {
+		return err
+	}
+	if decoder.Fingerprint() != schema.Fingerprint() {
+		return fmt.Errorf("%w: fingerprint of %s is %s, got %s", ErrDecoderMismatch, typ,
+			schema.Fingerprint(), decoder.Fingerprint())
+	}
+	rowDecoders.Store(typ, &registeredDecoder{
+		decoder: decoder,
+		decodeValue: func(row Row, columns [][]int, params *Params) (reflect.Value, error) {
+			obj, err := decoder.DecodeRow(row, columns, params)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(obj), nil
+		},
+	})
+	return nil
+}
+
+func lookupDecoder[T any]() RowDecoder[T] {
+	if d := lookupRegistered(reflect.TypeFor[T]()); d != nil {
+		return d.decoder.(RowDecoder[T])
+	}
+	return nil
+}
+
+func lookupRegistered(typ reflect.Type) *registeredDecoder {
+	if d, ok := rowDecoders.Load(typ); ok {
+		return d.(*registeredDecoder)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package eorm
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// compareDecoder 注册生成的 RowDecoder(测试结束时取消注册，其他测试仍使用反射)，比较它与反射方式的转换结果
+func compareDecoder[T any](t *testing.T, name string, decoder RowDecoder[T], sheet Sheet, opts ...Option) {
+	if err := RegisterDecoder[T](decoder); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	defer rowDecoders.Delete(reflect.TypeFor[T]())
+	generated, err := Open[T](sheet, opts...)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if generated.rowMapper.decoder == nil {
+		t.Fatalf("%s: generated decoder of %s not registered", name, reflect.TypeFor[T]())
+	}
+	reflected, err := Open[T](sheet, append(opts, WithReflection())...)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if reflected.rowMapper.decoder != nil {
+		t.Fatalf("%s: reflection expected", name)
+	}
+	rows := 0
+	for generated.Next() {
+		if !reflected.Next() {
+			t.Fatalf("%s: reflection stopped early", name)
+		}
+		gobj, gerr := generated.Current()
+		robj, rerr := reflected.Current()
+		if fmt.Sprint(gerr) != fmt.Sprint(rerr) || !reflect.DeepEqual(gobj, robj) {
+			t.Fatalf("%s: row %d: generated %+v %v, reflection %+v %v", name, generated.rowIndex, gobj, gerr, robj, rerr)
+		}
+		rows++
+	}
+	if reflected.Next() {
+		t.Fatalf("%s: generated decoder stopped early", name)
+	}
+	t.Logf("%s: %d rows checked", name, rows)
+}
+
+func TestGeneratedDecoder(t *testing.T) {
+	optsList := [][]Option{
+		nil,
+		{WithTrimSpace()},
+		{WithIgnoreParseError(), WithIgnoreOutOfRange()},
+	}
+	for _, file := range []string{"title.xlsx", "title.xls", "title.ods", "title.csv"} {
+		wb, err := NewWorkbook(filepath.Join("testdata", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheet, err := wb.GetSheet(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, opts := range optsList {
+			compareDecoder[TitleObj1](t, fmt.Sprintf("%s/TitleObj1/%d", file, i), titleObj1Decoder{}, sheet, opts...)
+			compareDecoder[TitleObj2](t, fmt.Sprintf("%s/TitleObj2/%d", file, i), titleObj2Decoder{}, sheet, opts...)
+		}
+		_ = wb.Close()
+	}
+	sheet := parallelSheet(t, 100)
+	for i, opts := range optsList {
+		compareDecoder[parallelObj](t, fmt.Sprintf("parallelObj/%d", i), parallelObjDecoder{}, sheet, opts...)
+	}
+}
+
+// staleDecoder 结构体修改后没有重新生成的decoder
+type staleDecoder struct{ titleObj1Decoder }
+
+func (staleDecoder) Fingerprint() string { return "stale" }
+
+func TestStaleDecoder(t *testing.T) {
+	if err := RegisterDecoder[TitleObj1](staleDecoder{}); !errors.Is(err, ErrDecoderMismatch) {
+		t.Fatalf("ErrDecoderMismatch expected, got %v", err)
+	}
+	if lookupDecoder[TitleObj1]() != nil {
+		t.Fatal("stale decoder should not be registered")
+	}
+	wb, err := NewWorkbook(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	sheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := Open[TitleObj1](sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if em.rowMapper.decoder != nil {
+		t.Fatal("reflection expected")
+	}
+	// 生成的decoder与schema的指纹一致，不同类型的指纹不同
+	schema, err := CompileMapperSchema(reflect.TypeFor[TitleObj1]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Fingerprint() != (titleObj1Decoder{}).Fingerprint() {
+		t.Fatalf("fingerprint %s expected, got %s", schema.Fingerprint(), titleObj1Decoder{}.Fingerprint())
+	}
+	other, err := CompileMapperSchema(reflect.TypeFor[TitleObj2]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Fingerprint() == schema.Fingerprint() {
+		t.Fatal("different fingerprints expected")
+	}
+}
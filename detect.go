@@ -0,0 +1,264 @@
+package eorm
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// FileFormat 根据文件内容识别出的workbook格式
+type FileFormat byte
+
+const (
+	FormatUnknown FileFormat = iota
+	FormatXls
+	FormatXlsx
+	FormatOds
+	FormatCsv
+)
+
+var (
+	ErrEncryptedFile     = errors.New("excel: encrypted file")
+	ErrUnsupportedFormat = errors.New("excel: unsupported file format")
+)
+
+var (
+	oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+	zipSignature = []byte{'P', 'K', 0x03, 0x04}
+)
+
+const (
+	sniffTextSize     = 8 * 1024 // 用于判断文本格式的字节数
+	xlsRecordFilePass = 0x002F   // BIFF FILEPASS 记录，存在时说明xls文件已加密
+	xlsRecordEOF      = 0x000A
+)
+
+func (f FileFormat) String() string {
+	switch f {
+	case FormatXls:
+		return "xls"
+	case FormatXlsx:
+		return "xlsx"
+	case FormatOds:
+		return "ods"
+	case FormatCsv:
+		return "csv"
+	default:
+		return fmt.Sprintf("N/A(0x%x)", byte(f))
+	}
+}
+
+// DetectFormat 根据文件内容识别workbook格式，识别完成后reader被重置到起始位置。
+//
+// * OLE2复合文档：包含 Workbook/Book 流的为xls；包含 EncryptionInfo/EncryptedPackage 流的是加密的xlsx，
+// 返回 FormatXlsx 和 ErrEncryptedFile；xls中存在FILEPASS记录时返回 FormatXls 和 ErrEncryptedFile。
+// * zip：包含 xl/workbook.xml 的为xlsx；mimetype 为 OpenDocument Spreadsheet 的为ods。
+// * 其他：开头部分为文本(存在UTF-16 BOM，或不包含NUL等控制字符)时认为是csv。
+//
+// 无法识别时返回 ErrUnsupportedFormat
+func DetectFormat(reader io.ReadSeeker) (format FileFormat, err error) {
+	defer func() {
+		if _, serr := reader.Seek(0, io.SeekStart); serr != nil && err == nil {
+			format, err = FormatUnknown, serr
+		}
+	}()
+	if _, err = reader.Seek(0, io.SeekStart); err != nil {
+		return FormatUnknown, err
+	}
+	head := make([]byte, sniffTextSize)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return FormatUnknown, err
+	}
+	head = head[:n]
+	switch {
+	case bytes.HasPrefix(head, oleSignature):
+		return detectOle(reader)
+	case bytes.HasPrefix(head, zipSignature):
+		return detectZip(reader)
+	case isText(head):
+		return FormatCsv, nil
+	default:
+		return FormatUnknown, ErrUnsupportedFormat
+	}
+}
+
+func detectOle(reader io.ReadSeeker) (FileFormat, error) {
+	ra, _, err := toReaderAt(reader)
+	if err != nil {
+		return FormatUnknown, err
+	}
+	doc, err := mscfb.New(ra)
+	if err != nil {
+		return FormatUnknown, fmt.Errorf("%w: %w", ErrUnsupportedFormat, err)
+	}
+	var workbook *mscfb.File
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		switch entry.Name {
+		case "EncryptionInfo", "EncryptedPackage":
+			return FormatXlsx, ErrEncryptedFile
+		case "Workbook", "Book":
+			if workbook == nil {
+				workbook = entry
+			}
+		}
+	}
+	if workbook == nil {
+		return FormatUnknown, ErrUnsupportedFormat
+	}
+	encrypted, err := isXlsEncrypted(workbook)
+	if err != nil {
+		return FormatUnknown, err
+	}
+	if encrypted {
+		return FormatXls, ErrEncryptedFile
+	}
+	return FormatXls, nil
+}
+
+// isXlsEncrypted 在workbook全局子流中查找FILEPASS记录
+func isXlsEncrypted(stream io.Reader) (bool, error) {
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(stream, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return false, nil
+			}
+			return false, err
+		}
+		typ := binary.LittleEndian.Uint16(header[:2])
+		size := binary.LittleEndian.Uint16(header[2:])
+		switch typ {
+		case xlsRecordFilePass:
+			return true, nil
+		case xlsRecordEOF:
+			return false, nil
+		}
+		if _, err := io.CopyN(io.Discard, stream, int64(size)); err != nil {
+			return false, nil
+		}
+	}
+}
+
+func detectZip(reader io.ReadSeeker) (FileFormat, error) {
+	ra, size, err := toReaderAt(reader)
+	if err != nil {
+		return FormatUnknown, err
+	}
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return FormatUnknown, fmt.Errorf("%w: %w", ErrUnsupportedFormat, err)
+	}
+	for _, f := range zr.File {
+		switch f.Name {
+		case "xl/workbook.xml":
+			return FormatXlsx, nil
+		case "mimetype":
+			if checkOdsMimeType(f) == nil {
+				return FormatOds, nil
+			}
+		}
+	}
+	return FormatUnknown, ErrUnsupportedFormat
+}
+
+// isText 存在UTF-16 BOM，或者不包含除\t\r\n以外的ASCII控制字符时认为是文本。
+// 非UTF-8编码(如GBK)的文本同样会被认为是文本。
+func isText(head []byte) bool {
+	if len(head) == 0 {
+		return false
+	}
+	if bytes.HasPrefix(head, []byte{0xFF, 0xFE}) || bytes.HasPrefix(head, []byte{0xFE, 0xFF}) {
+		return true
+	}
+	for _, c := range head {
+		if c < 0x20 && c != '\t' && c != '\r' && c != '\n' {
+			return false
+		}
+		if c == 0x7F {
+			return false
+		}
+	}
+	return true
+}
+
+// sniffComma 统计首行中候选分隔符的数量，选择出现最多的作为csv分隔符，缺省为','
+func sniffComma(head []byte) rune {
+	if bytes.HasPrefix(head, []byte{0xFF, 0xFE}) || bytes.HasPrefix(head, []byte{0xFE, 0xFF}) {
+		// UTF-16中的分隔符均为单字节字符加上一个0字节，去掉0字节后统计
+		head = bytes.ReplaceAll(head[2:], []byte{0}, nil)
+	}
+	line, _, _ := bytes.Cut(head, []byte{'\n'})
+	if !utf8.Valid(line) {
+		line = bytes.ToValidUTF8(line, nil)
+	}
+	comma, maxCount := ',', 0
+	for _, c := range []rune{',', '\t', ';', '|'} {
+		if count := strings.Count(string(line), string(c)); count > maxCount {
+			comma, maxCount = c, count
+		}
+	}
+	return comma
+}
+
+// OpenWorkbook 根据文件内容识别格式并打开workbook。csv格式未指定分隔符时，根据首行内容推测分隔符。
+// 加密的xlsx需要通过 WithPassword 提供密码，未提供时返回同时包装了 ErrEncryptedFile 和 ErrPasswordRequired 的错误，
+// 加密的xls不被支持，返回 ErrEncryptedFile。无法识别时返回 ErrUnsupportedFormat
+func OpenWorkbook(reader io.ReadSeeker, opts ...WorkbookOption) (Workbook, error) {
+	return openWorkbook(reader, NewWorkbookParams(opts...))
+}
+
+// OpenWorkbookContext 与 OpenWorkbook 相同，打开及读取workbook时检查ctx(见 WithWorkbookContext)
+func OpenWorkbookContext(ctx context.Context, reader io.ReadSeeker, opts ...WorkbookOption) (Workbook, error) {
+	return OpenWorkbook(reader, append(opts, WithWorkbookContext(ctx))...)
+}
+
+func openWorkbook(reader io.ReadSeeker, params *WorkbookParams) (Workbook, error) {
+	format, err := DetectFormat(reader)
+	if err != nil {
+		if !errors.Is(err, ErrEncryptedFile) || format != FormatXlsx {
+			return nil, fmt.Errorf("eorm: failed to open workbook: %w", err)
+		}
+		if params.Password == "" {
+			return nil, fmt.Errorf("eorm: failed to open workbook: %w: %w", ErrEncryptedFile, ErrPasswordRequired)
+		}
+	}
+	var wb Workbook
+	switch format {
+	case FormatXls:
+		wb, err = NewXlsWorkbookByReadSeeker(reader, WithWorkbookParams(params))
+	case FormatXlsx:
+		// 格式已经识别，只有加密的文件需要 newXlsxWorkbook 解密
+		if err != nil {
+			wb, err = newXlsxWorkbook(reader, params)
+		} else {
+			wb, err = openXlsxWorkbook(reader, params)
+		}
+	case FormatOds:
+		wb, err = NewOdsWorkbookByReadSeeker(reader, WithWorkbookParams(params))
+	case FormatCsv:
+		if params.Comma == 0 {
+			head := make([]byte, sniffTextSize)
+			n, _ := io.ReadFull(reader, head)
+			params.Comma = sniffComma(head[:n])
+			if _, err = reader.Seek(0, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("eorm: failed to open workbook: %w", err)
+			}
+		}
+		wb, err = newCsvWorkbook(reader, params)
+	default:
+		return nil, fmt.Errorf("eorm: failed to open workbook: %w", ErrUnsupportedFormat)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eorm: failed to open workbook: %w", err)
+	}
+	return wb, nil
+}
@@ -0,0 +1,99 @@
+package eorm
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		file   string
+		format FileFormat
+	}{
+		{file: "title.xls", format: FormatXls},
+		{file: "example1.xls", format: FormatXls},
+		{file: "title.xlsx", format: FormatXlsx},
+		{file: "title.ods", format: FormatOds},
+		{file: "title.csv", format: FormatCsv},
+		{file: "title_gbk.tsv", format: FormatCsv},
+	}
+	for _, test := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		format, err := DetectFormat(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("DetectFormat(%s): %v", test.file, err)
+		}
+		if format != test.format {
+			t.Fatalf("DetectFormat(%s): expecting %s, got %s", test.file, test.format, format)
+		}
+	}
+
+	if _, err := DetectFormat(bytes.NewReader([]byte{0x00, 0x01, 0x02, 0x03})); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("ErrUnsupportedFormat expected, got %v", err)
+	}
+}
+
+func TestOpenWorkbookByContent(t *testing.T) {
+	for _, file := range []string{"title.xlsx", "title.ods", "title.csv"} {
+		data, err := os.ReadFile(filepath.Join("testdata", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 文件名与内容不符
+		wb, err := NewWorkbookByReadSeeker("report.xls", bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		sheet, err := wb.GetSheet(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		em, err := NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj1{}))
+		if err != nil {
+			t.Fatalf("%s: NewEORM failed: %v", file, err)
+		}
+		testTitle1(em, t)
+		_ = wb.Close()
+	}
+
+	// 没有文件名，根据首行推测分隔符
+	data, err := os.ReadFile(filepath.Join("testdata", "title.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wb, err := OpenWorkbook(bytes.NewReader(bytes.ReplaceAll(data, []byte(","), []byte("\t"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet, _ := wb.GetSheet(0)
+	if row, _ := sheet.GetRow(2); row.ColumnCount() != 8 {
+		t.Fatalf("expecting 8 columns, got %d", row.ColumnCount())
+	}
+}
+
+func TestDetectEncrypted(t *testing.T) {
+	f, err := excelize.OpenFile(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if _, err = f.WriteTo(buf, excelize.Options{Password: "password"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	if _, err = DetectFormat(bytes.NewReader(buf.Bytes())); !errors.Is(err, ErrEncryptedFile) {
+		t.Fatalf("ErrEncryptedFile expected, got %v", err)
+	}
+	if _, err = OpenWorkbook(bytes.NewReader(buf.Bytes())); !errors.Is(err, ErrEncryptedFile) {
+		t.Fatalf("ErrEncryptedFile expected, got %v", err)
+	}
+}
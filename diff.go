@@ -0,0 +1,122 @@
+package eorm
+
+import (
+	"slices"
+)
+
+type (
+	// ColumnChange 一列的变化，Old/New 为编码后的title path，新增的列 OldIndex 为-1，删除的列 NewIndex 为-1
+	ColumnChange struct {
+		OldIndex int    `json:"old_index" yaml:"old_index"`
+		NewIndex int    `json:"new_index" yaml:"new_index"`
+		Old      string `json:"old,omitempty" yaml:"old,omitempty"`
+		New      string `json:"new,omitempty" yaml:"new,omitempty"`
+	}
+
+	// TitleDiff 两个表头的差异：
+	//   - Added/Removed 只存在于新/旧表头中的title path
+	//   - Renamed 同一位置上的title path发生了变化(位置上的新增与删除)
+	//   - Reordered 两个表头中都存在，但与其他列的相对顺序发生了变化
+	TitleDiff struct {
+		Added     []ColumnChange `json:"added" yaml:"added"`
+		Removed   []ColumnChange `json:"removed" yaml:"removed"`
+		Renamed   []ColumnChange `json:"renamed" yaml:"renamed"`
+		Reordered []ColumnChange `json:"reordered" yaml:"reordered"`
+	}
+)
+
+// IsEmpty 两个表头相同
+func (d *TitleDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Renamed) == 0 && len(d.Reordered) == 0
+}
+
+// IsBreaking 是否存在使原有eorm标签无法匹配的变化，即删除或者重命名了列。
+// 因为匹配只依赖title path，所以新增列和调整顺序不会影响原有的标签
+func (d *TitleDiff) IsBreaking() bool {
+	return len(d.Removed) > 0 || len(d.Renamed) > 0
+}
+
+// DiffTitlePaths 比较新旧两个表头。相同的title path按出现的顺序一一对应，
+// 对应的列中不在最长递增子序列内的被认为调整了顺序；剩余的列中位置相同的被认为是重命名
+func DiffTitlePaths(oldPaths, newPaths TitlePaths) *TitleDiff {
+	oldKeys, newKeys := encodePaths(oldPaths), encodePaths(newPaths)
+	positions := make(map[string][]int)
+	for i, key := range newKeys {
+		positions[key] = append(positions[key], i)
+	}
+	// pairs[i] 为与旧表头第i列对应的新表头列，没有对应列时为-1
+	pairs := make([]int, len(oldKeys))
+	matchedNew := make([]bool, len(newKeys))
+	for i, key := range oldKeys {
+		pairs[i] = -1
+		if ps := positions[key]; len(ps) > 0 {
+			pairs[i] = ps[0]
+			matchedNew[ps[0]] = true
+			positions[key] = ps[1:]
+		}
+	}
+
+	diff := new(TitleDiff)
+	var matched []int
+	for _, j := range pairs {
+		if j >= 0 {
+			matched = append(matched, j)
+		}
+	}
+	inOrder := longestIncreasing(matched)
+	for i, j := range pairs {
+		switch {
+		case j >= 0:
+			if !inOrder[j] {
+				diff.Reordered = append(diff.Reordered, ColumnChange{OldIndex: i, NewIndex: j, Old: oldKeys[i], New: newKeys[j]})
+			}
+		case i < len(newKeys) && !matchedNew[i]:
+			matchedNew[i] = true
+			diff.Renamed = append(diff.Renamed, ColumnChange{OldIndex: i, NewIndex: i, Old: oldKeys[i], New: newKeys[i]})
+		default:
+			diff.Removed = append(diff.Removed, ColumnChange{OldIndex: i, NewIndex: -1, Old: oldKeys[i]})
+		}
+	}
+	for j, key := range newKeys {
+		if !matchedNew[j] {
+			diff.Added = append(diff.Added, ColumnChange{OldIndex: -1, NewIndex: j, New: key})
+		}
+	}
+	return diff
+}
+
+func encodePaths(tps TitlePaths) []string {
+	keys := make([]string, len(tps))
+	for i, tp := range tps {
+		keys[i] = tp.Encode()
+	}
+	return keys
+}
+
+// longestIncreasing 返回seq(元素不重复)的一个最长递增子序列中的元素
+func longestIncreasing(seq []int) map[int]bool {
+	// tails[k] 为长度为k+1的递增子序列的最小结尾在seq中的下标
+	var tails []int
+	prev := make([]int, len(seq))
+	for i, v := range seq {
+		k, _ := slices.BinarySearchFunc(tails, v, func(t, target int) int { return seq[t] - target })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	ret := make(map[int]bool, len(tails))
+	if len(tails) == 0 {
+		return ret
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+		ret[seq[i]] = true
+	}
+	return ret
+}
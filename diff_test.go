@@ -0,0 +1,63 @@
+package eorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffTitlePaths(t *testing.T) {
+	paths := func(names ...string) TitlePaths {
+		tps := make(TitlePaths, len(names))
+		for i, name := range names {
+			tps[i] = MustTitlePath(name)
+		}
+		return tps
+	}
+	tests := []struct {
+		name     string
+		old, new TitlePaths
+		expect   TitleDiff
+		breaking bool
+	}{
+		{
+			name: "same",
+			old:  paths("a/x", "a/y", "b/z", "b/z"),
+			new:  paths("a/x", "a/y", "b/z", "b/z"),
+		},
+		{
+			name:   "added",
+			old:    paths("a", "b"),
+			new:    paths("c", "a", "b", "d"),
+			expect: TitleDiff{Added: []ColumnChange{{-1, 0, "", "c"}, {-1, 3, "", "d"}}},
+		},
+		{
+			name:     "removed and renamed",
+			old:      paths("a", "b", "c", "d"),
+			new:      paths("a", "B", "c"),
+			expect:   TitleDiff{Removed: []ColumnChange{{3, -1, "d", ""}}, Renamed: []ColumnChange{{1, 1, "b", "B"}}},
+			breaking: true,
+		},
+		{
+			name:   "reordered",
+			old:    paths("a", "b", "c", "d"),
+			new:    paths("a", "c", "d", "b"),
+			expect: TitleDiff{Reordered: []ColumnChange{{1, 3, "b", "b"}}},
+		},
+		{
+			name:     "duplicated",
+			old:      paths("a", "x", "x"),
+			new:      paths("a", "x"),
+			expect:   TitleDiff{Removed: []ColumnChange{{2, -1, "x", ""}}},
+			breaking: true,
+		},
+	}
+	for _, test := range tests {
+		diff := DiffTitlePaths(test.old, test.new)
+		if !reflect.DeepEqual(*diff, test.expect) {
+			t.Fatalf("%s: expecting %+v, got %+v", test.name, test.expect, *diff)
+		}
+		if diff.IsBreaking() != test.breaking || diff.IsEmpty() != reflect.DeepEqual(test.expect, TitleDiff{}) {
+			t.Fatalf("%s: breaking=%t empty=%t", test.name, diff.IsBreaking(), diff.IsEmpty())
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package eorm
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"iter"
 	"reflect"
 
@@ -21,19 +23,32 @@ var (
 	ErrRowNotFound            = errors.New("eorm: row not found")
 	ErrRequiredColumnNotFound = errors.New("eorm: required column not found")
 	ErrInsufficientMatchLevel = errors.New("eorm: insufficient match level")
+	ErrAmbiguousSheet         = errors.New("eorm: ambiguous sheet")
+	ErrDecoderMismatch        = errors.New("eorm: decoder mismatch")
+	ErrTitleMismatch          = errors.New("eorm: title mismatch") // sheet的表头与title path不匹配
 )
 
-type EORM[T any] struct {
-	sheet      Sheet
-	objType    reflect.Type
-	params     *Params
-	rowMapper  *RowMapper[T]
-	columnTree *PathTree[int]
-	currentRow Row
-	currentObj *T
-	rowIndex   int
-	lastErr    error
-}
+type (
+	EORM[T any] struct {
+		rowCursor
+		objType    reflect.Type
+		rowMapper  *RowMapper[T]
+		columnTree *PathTree[int]
+		currentObj *T
+		sources    map[*T]int // TrackRows 时对象 -> 来源的行下标
+	}
+
+	// rowCursor 从startRow开始遍历sheet中的数据行，与对象类型无关，由 EORM 和 DecodeWorkbook 共用
+	rowCursor struct {
+		sheet      Sheet
+		params     *Params
+		startRow   int
+		currentRow Row
+		rowIndex   int // -1: 尚未开始，-2: 遍历完成
+		lastErr    error
+		limiter    *limiter
+	}
+)
 
 func NewEORM[T any](sheet Sheet, objType reflect.Type, opts ...Option) (*EORM[T], error) {
 	// 检查objType是否为结构体
@@ -50,15 +65,19 @@ func NewEORM[T any](sheet Sheet, objType reflect.Type, opts ...Option) (*EORM[T]
 	}
 
 	return &EORM[T]{
-		sheet:      sheet,
+		rowCursor:  newRowCursor(sheet, params, params.MinRows(columnTree.Depth())),
 		objType:    objType,
-		params:     params,
 		rowMapper:  rowMapper,
 		columnTree: columnTree,
-		rowIndex:   -1,
+		sources:    make(map[*T]int),
 	}, nil
 }
 
+// Open 与 NewEORM 相同，对象类型由T确定，T必须是结构体
+func Open[T any](sheet Sheet, opts ...Option) (*EORM[T], error) {
+	return NewEORM[T](sheet, reflect.TypeFor[T](), opts...)
+}
+
 func (e *EORM[T]) IsValid() bool {
 	if e == nil || e.sheet == nil || e.objType == nil || e.rowMapper == nil || e.columnTree == nil {
 		return false
@@ -85,40 +104,77 @@ func (e *EORM[T]) IsMatched() bool {
 
 func (e *EORM[T]) LastError() error  { return e.lastErr }
 func (e *EORM[T]) ClrLastError()     { e.lastErr = nil }
-func (e *EORM[T]) DataStartRow() int { return e.params.MinRows(e.columnTree.Depth()) }
+func (e *EORM[T]) DataStartRow() int { return e.startRow }
 
 // Next 移动到下一行，如果还有行则返回true，否则返回false。
-// 无论使用Next()|Current() 还是 All() 或者 NoErrorRows() 只能遍历一次
+// 无论使用Next()|Current() 还是 All() 或者 NoErrorRows() 只能遍历一次，需要再次遍历时先调用 Reset()。
+// 超出 Params.Limits 的限制时，无论是否设置了 IgnoreReadRowError，都会停止遍历并通过 LastError() 返回对应的错误
 func (e *EORM[T]) Next() bool {
+	return e.NextContext(context.Background())
+}
+
+// NextContext 与 Next() 相同，ctx被取消后返回false，并通过 LastError() 返回 ctx.Err()。
+// sheet实现了 ContextSheet 时，ctx同时传递给读取行的过程
+func (e *EORM[T]) NextContext(ctx context.Context) bool {
 	if !e.IsValid() {
 		return false
 	}
+	e.currentObj = nil
+	return e.next(ctx)
+}
+
+func newRowCursor(sheet Sheet, params *Params, startRow int) rowCursor {
+	return rowCursor{
+		sheet:    sheet,
+		params:   params,
+		startRow: startRow,
+		rowIndex: -1,
+		limiter:  params.Limits.start(),
+	}
+}
+
+// next 移动到下一个非空行，规则见 EORM.Next
+func (c *rowCursor) next(ctx context.Context) bool {
+	if err := ctx.Err(); err != nil {
+		c.lastErr = err
+		return false
+	}
 	// 如果没有初始化迭代器，先初始化
-	if e.rowIndex == -1 {
-		startRow := e.DataStartRow()
-		if startRow >= e.sheet.RowCount() {
+	if c.rowIndex == -1 {
+		if c.startRow >= c.sheet.RowCount() {
 			return false
 		}
 		// 因为遍历时先自增，所以这里-1。又因为tree depth不可能小于1，所以这个值不会小于0
-		e.rowIndex = startRow - 1
+		c.rowIndex = c.startRow - 1
 	}
-	if e.rowIndex < 0 || e.rowIndex >= e.sheet.RowCount() {
+	if c.rowIndex < 0 || c.rowIndex >= c.sheet.RowCount() {
 		return false
 	}
 
-	e.currentRow = nil
-	e.currentObj = nil
-	e.lastErr = nil
-	for e.rowIndex >= 0 && e.rowIndex < e.sheet.RowCount() {
-		e.rowIndex++
-		if e.rowIndex >= e.sheet.RowCount() {
-			e.rowIndex = -2
+	c.currentRow = nil
+	c.lastErr = nil
+	for c.rowIndex >= 0 && c.rowIndex < c.sheet.RowCount() {
+		c.rowIndex++
+		if c.rowIndex >= c.sheet.RowCount() {
+			c.rowIndex = -2
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			c.lastErr = err
+			return false
+		}
+		if err := c.checkLimits(c.rowIndex, nil); err != nil {
+			c.lastErr = err
 			return false
 		}
-		row, err := e.sheet.GetRow(e.rowIndex)
+		row, err := GetRowContext(ctx, c.sheet, c.rowIndex)
 		if err != nil {
-			e.lastErr = err
-			if e.params.IgnoreReadRowError {
+			if cerr := ctx.Err(); cerr != nil {
+				c.lastErr = cerr
+				return false
+			}
+			c.lastErr = err
+			if c.params.IgnoreReadRowError {
 				continue
 			} else {
 				return false
@@ -127,13 +183,30 @@ func (e *EORM[T]) Next() bool {
 		if row == nil {
 			continue
 		}
-		e.currentRow = row
+		if err = c.checkLimits(c.rowIndex, row); err != nil {
+			c.lastErr = err
+			return false
+		}
+		c.currentRow = row
 		return true
 	}
 
 	return false
 }
 
+// checkLimits row为nil时检查行数和超时，否则检查行的列数和单元格大小
+func (c *rowCursor) checkLimits(rowIndex int, row Row) error {
+	return c.limiter.checkRowAt(rowIndex, row)
+}
+
+// stopError 遍历正常结束时返回nil，否则返回导致遍历提前结束的错误
+func (c *rowCursor) stopError() error {
+	if c.rowIndex == -2 {
+		return nil
+	}
+	return c.lastErr
+}
+
 func (e *EORM[T]) CheckValue() error {
 	if !e.IsValid() || e.rowIndex < 0 || e.rowIndex >= e.sheet.RowCount() {
 		return ErrInvalidState
@@ -162,6 +235,7 @@ func (e *EORM[T]) Current() (t *T, err error) {
 		return nil, err
 	}
 	e.currentObj = obj
+	e.track(obj, e.rowIndex)
 	return e.currentObj, nil
 }
 
@@ -171,10 +245,15 @@ func (e *EORM[T]) CurrentRowNumber() (int, bool) {
 	return e.rowIndex, e.rowIndex >= e.DataStartRow()
 }
 
-// All 遍历每一行，无论是否有error。无论使用Next()|Current() 还是 All() 或者 NoErrorRows() 只能遍历一次
+// All 遍历每一行，无论是否有error。无论使用Next()|Current() 还是 All() 或者 NoErrorRows() 只能遍历一次，需要再次遍历时先调用 Reset()
 func (e *EORM[T]) All() iter.Seq2[*T, error] {
+	return e.AllContext(context.Background())
+}
+
+// AllContext 与 All() 相同，ctx被取消后停止遍历，并通过 LastError() 返回 ctx.Err()
+func (e *EORM[T]) AllContext(ctx context.Context) iter.Seq2[*T, error] {
 	return func(yield func(*T, error) bool) {
-		for e.Next() {
+		for e.NextContext(ctx) {
 			t, err := e.Current()
 			if !yield(t, err) {
 				return
@@ -184,10 +263,15 @@ func (e *EORM[T]) All() iter.Seq2[*T, error] {
 }
 
 // NoErrorRows 遍历未出错的row，及其在表格中的行号（从0开始，不包括表头）。
-// 无论使用Next()|Current() 还是 All() 或者 NoErrorRows() 只能遍历一次
+// 无论使用Next()|Current() 还是 All() 或者 NoErrorRows() 只能遍历一次，需要再次遍历时先调用 Reset()
 func (e *EORM[T]) NoErrorRows() iter.Seq2[int, *T] {
+	return e.NoErrorRowsContext(context.Background())
+}
+
+// NoErrorRowsContext 与 NoErrorRows() 相同，ctx被取消后停止遍历，并通过 LastError() 返回 ctx.Err()
+func (e *EORM[T]) NoErrorRowsContext(ctx context.Context) iter.Seq2[int, *T] {
 	return func(yield func(int, *T) bool) {
-		for e.Next() {
+		for e.NextContext(ctx) {
 			t, err := e.Current()
 			if err != nil {
 				continue
@@ -198,3 +282,124 @@ func (e *EORM[T]) NoErrorRows() iter.Seq2[int, *T] {
 		}
 	}
 }
+
+// Reset 回到尚未开始遍历的状态，之后可以重新使用 Next()|Current()、All() 或者 NoErrorRows() 遍历。
+// 表头匹配的结果被保留，Params.Limits 中的超时重新计时
+func (e *EORM[T]) Reset() {
+	e.rowIndex = -1
+	e.currentRow = nil
+	e.currentObj = nil
+	e.lastErr = nil
+	e.limiter = e.params.Limits.start()
+}
+
+// Len 返回数据行数(不包括表头)，其中可能包括空行
+func (e *EORM[T]) Len() int {
+	if !e.IsValid() {
+		return 0
+	}
+	return max(e.sheet.RowCount()-e.DataStartRow(), 0)
+}
+
+// Seek 移动到sheet的rowIndex行之前，之后调用 Next() 时从rowIndex行开始遍历。
+// rowIndex 的取值范围为 [DataStartRow(), sheet.RowCount()]，等于 sheet.RowCount() 时表示移动到末尾
+func (e *EORM[T]) Seek(rowIndex int) error {
+	if !e.IsValid() {
+		return ErrInvalidState
+	}
+	if rowIndex < e.DataStartRow() || rowIndex > e.sheet.RowCount() {
+		return ErrOutOfRange
+	}
+	// 因为 Next() 先自增，所以这里-1。DataStartRow()不小于1，所以这个值不会小于0
+	e.rowIndex = rowIndex - 1
+	e.currentRow = nil
+	e.currentObj = nil
+	e.lastErr = nil
+	return nil
+}
+
+// Get 返回sheet中rowIndex行对应的对象，不影响当前的遍历状态。
+// rowIndex 为sheet的行下标，小于 DataStartRow() 或不小于 sheet.RowCount() 时返回 ErrOutOfRange，空行返回 ErrRowNotFound
+func (e *EORM[T]) Get(rowIndex int) (*T, error) {
+	if !e.IsValid() {
+		return nil, ErrInvalidState
+	}
+	if rowIndex < e.DataStartRow() || rowIndex >= e.sheet.RowCount() {
+		return nil, ErrOutOfRange
+	}
+	if err := e.checkLimits(rowIndex, nil); err != nil {
+		return nil, err
+	}
+	row, err := e.sheet.GetRow(rowIndex)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, ErrRowNotFound
+	}
+	if err = e.checkLimits(rowIndex, row); err != nil {
+		return nil, err
+	}
+	obj, err := e.rowMapper.Transit(row)
+	if err != nil {
+		return nil, err
+	}
+	e.track(obj, rowIndex)
+	return obj, nil
+}
+
+func (e *EORM[T]) track(obj *T, rowIndex int) {
+	if e.params.TrackRows {
+		e.sources[obj] = rowIndex
+	}
+}
+
+// RowIndexOf 返回由 Current()、Get() 或者 ParallelRows()/DecodeAll() 转换得到的obj所在sheet的行下标，需要 WithTrackRows()
+func (e *EORM[T]) RowIndexOf(obj *T) (int, bool) {
+	rowIndex, ok := e.sources[obj]
+	return rowIndex, ok
+}
+
+// Update 将obj中所有映射到列的属性写回sheet中rowIndex行对应的单元格，其他单元格(包括样式)不变。
+// sheet需要实现 EditableSheet(目前只有xlsx)，否则返回 ErrUnsupported。
+// rowIndex 的取值范围与 Get() 相同，nil指针及slice中不存在的元素对应的单元格被清空。
+// 通过Set方法赋值的属性使用 Get<Field>() 方法(返回值类型与Set方法的参数类型相同)的返回值，没有该方法时对应的单元格不变
+func (e *EORM[T]) Update(rowIndex int, obj *T) error {
+	if !e.IsValid() {
+		return ErrInvalidState
+	}
+	if obj == nil {
+		return ErrNil
+	}
+	es, ok := e.sheet.(EditableSheet)
+	if !ok {
+		return ErrUnsupported
+	}
+	if rowIndex < e.DataStartRow() || rowIndex >= e.sheet.RowCount() {
+		return ErrOutOfRange
+	}
+	for columnIndex, v := range e.rowMapper.objectCells(obj) {
+		if err := es.SetCell(rowIndex, columnIndex, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateObject 将obj写回其来源的行，需要 WithTrackRows()，obj不是由该EORM转换得到时返回 ErrRowNotFound
+func (e *EORM[T]) UpdateObject(obj *T) error {
+	rowIndex, ok := e.RowIndexOf(obj)
+	if !ok {
+		return ErrRowNotFound
+	}
+	return e.Update(rowIndex, obj)
+}
+
+// SaveTo 将 Update() 修改后的整个workbook写入w，sheet需要实现 EditableSheet
+func (e *EORM[T]) SaveTo(w io.Writer) error {
+	es, ok := e.sheet.(EditableSheet)
+	if !ok {
+		return ErrUnsupported
+	}
+	return es.SaveTo(w)
+}
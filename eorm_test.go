@@ -1,6 +1,8 @@
 package eorm
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"path/filepath"
@@ -33,6 +35,8 @@ func (u *TestUser) SetNames(names []string) {
 	}
 }
 
+//go:generate go run ./cmds/eormgen --type TitleObj1,TitleObj2,parallelObj --tests --no-register
+
 type Integer int64
 
 type TitleObj1 struct {
@@ -69,12 +73,13 @@ func (t *TitleObj1) String() string {
 	return fmt.Sprintf("{id:%d name:%s numbers:%v bool:%t slash:%s num:%d}", t.Id, t.Name, t.Numbers, t.Bool, math.BigIntForPrint(t.Slash), t.Num)
 }
 
-func testTitle1(em *EORM[TitleObj1], t *testing.T) {
-	expectings := []*TitleObj1{
-		&TitleObj1{Id: 10, Name: "name10", Numbers: []Integer{16, 17}, Bool: true, Slash: big.NewInt(14), Num: Integer(15)},
-		&TitleObj1{Id: 20, Name: "name20", Numbers: []Integer{26, 27}, Bool: false, Slash: big.NewInt(24), Num: Integer(25)},
-	}
+var title1Expectings = []*TitleObj1{
+	&TitleObj1{Id: 10, Name: "name10", Numbers: []Integer{16, 17}, Bool: true, Slash: big.NewInt(14), Num: Integer(15)},
+	&TitleObj1{Id: 20, Name: "name20", Numbers: []Integer{26, 27}, Bool: false, Slash: big.NewInt(24), Num: Integer(25)},
+}
 
+func testTitle1(em *EORM[TitleObj1], t *testing.T) {
+	expectings := title1Expectings
 	i := 0
 	for em.Next() {
 		rowObj, err := em.Current()
@@ -90,6 +95,9 @@ func testTitle1(em *EORM[TitleObj1], t *testing.T) {
 		t.Logf("%d: %s check", i, rowObj)
 		i++
 	}
+	if i != len(expectings) {
+		t.Fatalf("eorm: expected %d rows, got %d", len(expectings), i)
+	}
 }
 
 func TestTitle1(t *testing.T) {
@@ -116,6 +124,180 @@ func TestTitle1(t *testing.T) {
 	testTitle1(eorm, t)
 }
 
+func TestEORMRandomAccess(t *testing.T) {
+	for _, file := range []string{"title.xlsx", "title.ods"} {
+		wb, err := NewWorkbook(filepath.Join("testdata", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheet, err := wb.GetSheet(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		em, err := NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj1{}))
+		if err != nil {
+			t.Fatalf("NewEORM failed: %v", err)
+		}
+		start := em.DataStartRow()
+		if em.Len() != len(title1Expectings) {
+			t.Fatalf("%s: expecting Len()=%d, got %d", file, len(title1Expectings), em.Len())
+		}
+		// 先遍历一次，Reset后再遍历一次
+		testTitle1(em, t)
+		em.Reset()
+		testTitle1(em, t)
+
+		for i, expecting := range title1Expectings {
+			obj, err := em.Get(start + i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !expecting.Equals(obj) {
+				t.Fatalf("%s: row %d expecting %+v, got %+v", file, start+i, expecting, obj)
+			}
+		}
+		for _, idx := range []int{start - 1, start + em.Len()} {
+			if _, err = em.Get(idx); !errors.Is(err, ErrOutOfRange) {
+				t.Fatalf("%s: row %d ErrOutOfRange expected, got %v", file, idx, err)
+			}
+		}
+
+		if err = em.Seek(start + 1); err != nil {
+			t.Fatal(err)
+		}
+		if !em.Next() {
+			t.Fatalf("%s: Next() after Seek failed: %v", file, em.LastError())
+		}
+		obj, err := em.Current()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if idx, ok := em.CurrentRowNumber(); !ok || idx != start+1 || !title1Expectings[1].Equals(obj) {
+			t.Fatalf("%s: expecting row %d %+v, got row %d %+v", file, start+1, title1Expectings[1], idx, obj)
+		}
+		if err = em.Seek(start + em.Len()); err != nil {
+			t.Fatal(err)
+		}
+		if em.Next() {
+			t.Fatalf("%s: Next() should return false at the end", file)
+		}
+		if err = em.Seek(start - 1); !errors.Is(err, ErrOutOfRange) {
+			t.Fatalf("%s: ErrOutOfRange expected, got %v", file, err)
+		}
+		_ = wb.Close()
+	}
+}
+
+func TestMapperSchemaCache(t *testing.T) {
+	schema, err := CompileMapperSchema(reflect.TypeOf(TitleObj1{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := CompileMapperSchema(reflect.TypeOf(TitleObj1{})); err != nil || again != schema {
+		t.Fatalf("cached schema expected, got %p %v", again, err)
+	}
+	if _, err = CompileMapperSchema(reflect.TypeOf(0)); err == nil {
+		t.Fatal("error expected for non-struct type")
+	}
+
+	// 同一个schema并发绑定到不同的sheet
+	files := []string{"title.xlsx", "title.xls", "title.ods", "title.csv"}
+	errs := make(chan error, len(files))
+	for _, file := range files {
+		go func() {
+			errs <- func() error {
+				wb, err := NewWorkbook(filepath.Join("testdata", file))
+				if err != nil {
+					return err
+				}
+				defer func() {
+					_ = wb.Close()
+				}()
+				sheet, err := wb.GetSheet(0)
+				if err != nil {
+					return err
+				}
+				mapper, tree, err := BindRowMapper[TitleObj1](schema, sheet, NewParams())
+				if err != nil {
+					return err
+				}
+				if mapper.Schema() != schema || tree != schema.Tree() {
+					return fmt.Errorf("%s: schema not shared", file)
+				}
+				if !mapper.IsMatched() {
+					return fmt.Errorf("%s: match expected", file)
+				}
+				return nil
+			}()
+		}()
+	}
+	for range files {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestEORMContext(t *testing.T) {
+	for _, file := range []string{"title.xlsx", "title.xls", "title.csv"} {
+		wb, err := NewWorkbook(filepath.Join("testdata", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err = GetSheetContext(ctx, wb, 0); !errors.Is(err, context.Canceled) {
+			t.Fatalf("%s: context.Canceled expected, got %v", file, err)
+		}
+		if _, err = IterateSheetContext(ctx, wb, 0); !errors.Is(err, context.Canceled) {
+			t.Fatalf("%s: context.Canceled expected, got %v", file, err)
+		}
+		// 遍历过程中被取消时，Current 返回 ctx.Err()，之后 Next 返回false
+		ctx, cancel = context.WithCancel(context.Background())
+		it, err := IterateSheetContext(ctx, wb, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !it.Next() {
+			t.Fatalf("%s: first row expected", file)
+		}
+		if _, err = it.Current(); err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+		if !it.Next() {
+			t.Fatalf("%s: Next should report the cancellation", file)
+		}
+		if _, err = it.Current(); !errors.Is(err, context.Canceled) {
+			t.Fatalf("%s: context.Canceled expected, got %v", file, err)
+		}
+		if it.Next() {
+			t.Fatalf("%s: iterator should stop after cancel", file)
+		}
+		_ = it.Close()
+
+		sheet, err := GetSheetContext(context.Background(), wb, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		em, err := NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj1{}), WithIgnoreReadRowError())
+		if err != nil {
+			t.Fatalf("NewEORM failed: %v", err)
+		}
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		rows := 0
+		for range em.AllContext(ctx) {
+			rows++
+			cancel()
+		}
+		if rows != 1 || !errors.Is(em.LastError(), context.Canceled) {
+			t.Fatalf("%s: expecting 1 row and context.Canceled, got %d rows and %v", file, rows, em.LastError())
+		}
+		_ = wb.Close()
+	}
+}
+
 func TestTitle1StartAt3(t *testing.T) {
 	wb, err := NewWorkbook(filepath.Join("testdata", "title_start_at_2.xlsx"))
 	if err != nil {
@@ -1,10 +1,12 @@
 package eorm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"iter"
+	"os"
 	"path/filepath"
 	"strings"
 )
@@ -39,6 +41,44 @@ type (
 		Close() error
 	}
 
+	// ContextWorkbook 可选接口，读取sheet内容时检查ctx，ctx被取消后尽快返回 ctx.Err()
+	ContextWorkbook interface {
+		Workbook
+		GetSheetContext(ctx context.Context, index int) (Sheet, error)
+		GetSheetByNameContext(ctx context.Context, name string) (Sheet, error)
+		// IterateSheetContext ctx被取消后，RowIterator.Next() 再返回一次true，此时 RowIterator.Current() 返回 ctx.Err()，
+		// 之后 Next() 返回false。遍历时需要检查 Current() 的错误，才能区分被取消与正常结束
+		IterateSheetContext(ctx context.Context, index int) (RowIterator, error)
+	}
+
+	// ContextSheet 可选接口，读取行时检查ctx
+	ContextSheet interface {
+		Sheet
+		GetRowContext(ctx context.Context, index int) (Row, error)
+	}
+
+	// CellRange 单元格区域，行列下标从0开始，包含首尾
+	CellRange struct {
+		FirstRow    int `json:"first_row" yaml:"first_row"`
+		FirstColumn int `json:"first_column" yaml:"first_column"`
+		LastRow     int `json:"last_row" yaml:"last_row"`
+		LastColumn  int `json:"last_column" yaml:"last_column"`
+	}
+
+	// MergedSheet 可选接口，返回sheet中所有合并单元格的区域
+	MergedSheet interface {
+		Sheet
+		MergedRanges() ([]CellRange, error)
+	}
+
+	// EditableSheet 可选接口，修改单元格并保存整个workbook，目前只有xlsx支持。
+	// 未修改的单元格(包括样式、公式等)保持不变
+	EditableSheet interface {
+		Sheet
+		SetCell(rowIndex, columnIndex int, value any) error
+		SaveTo(w io.Writer) error
+	}
+
 	RowReader struct {
 		Row
 	}
@@ -55,20 +95,34 @@ var (
 	ErrExcelNotInitialized = errors.New("excel: not initialized")
 	ErrEof                 = errors.New("excel: eof")
 	ErrParseError          = errors.New("cell value parse error")
+	ErrPasswordRequired    = errors.New("excel: password required")
+	ErrIncorrectPassword   = errors.New("excel: incorrect password")
 )
 
-func NewWorkbook(filePath string) (Workbook, error) {
-	// 根据文件扩展名选择合适的Workbook实现
+// NewWorkbook 根据文件扩展名选择合适的Workbook实现，支持 .xlsx/.xls/.ods/.csv/.tsv，其他扩展名根据文件内容识别
+func NewWorkbook(filePath string, opts ...WorkbookOption) (Workbook, error) {
 	var wb Workbook
 	var err error
 	ext := strings.ToLower(filepath.Ext(filePath))
 	switch ext {
 	case ".xlsx":
-		wb, err = NewXlsxWorkbook(filePath)
+		wb, err = NewXlsxWorkbook(filePath, opts...)
 	case ".xls":
-		wb, err = NewXlsWorkbook(filePath)
+		wb, err = NewXlsWorkbook(filePath, opts...)
+	case ".ods":
+		wb, err = NewOdsWorkbook(filePath, opts...)
+	case ".csv", ".tsv":
+		wb, err = NewCsvWorkbook(filePath, opts...)
 	default:
-		return nil, fmt.Errorf("eorm: unsupported file format: %s", ext)
+		// 无法根据扩展名确定格式时，根据文件内容识别
+		f, err := os.Open(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("eorm: failed to open workbook: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		return openWorkbook(f, csvFileParams(filePath, opts...))
 	}
 	if err != nil {
 		return nil, fmt.Errorf("eorm: failed to open workbook: %w", err)
@@ -76,20 +130,105 @@ func NewWorkbook(filePath string) (Workbook, error) {
 	return wb, nil
 }
 
-func NewWorkbookByReadSeeker(filename string, reader io.ReadSeeker) (Workbook, error) {
-	var wb Workbook
-	var err error
-	ext := strings.ToLower(filepath.Ext(filename))
-	switch ext {
-	case ".xlsx":
-		wb, err = NewXlsxWorkbookByReadSeeker(reader)
-	case ".xls":
-		wb, err = NewXlsWorkbookByReadSeeker(reader)
-	default:
-		return nil, fmt.Errorf("eorm: unsupported file format: %s", ext)
+// NewWorkbookContext 与 NewWorkbook 相同，打开及读取workbook时检查ctx(见 WithWorkbookContext)
+func NewWorkbookContext(ctx context.Context, filePath string, opts ...WorkbookOption) (Workbook, error) {
+	return NewWorkbook(filePath, append(opts, WithWorkbookContext(ctx))...)
+}
+
+// NewWorkbookByReadSeeker 根据文件内容识别格式并打开workbook，filename 仅用于确定csv的sheet名称和缺省分隔符，
+// 所以扩展名与内容不符(如内容为xlsx的"report.xls")或没有扩展名的文件也可以正确打开
+func NewWorkbookByReadSeeker(filename string, reader io.ReadSeeker, opts ...WorkbookOption) (Workbook, error) {
+	return openWorkbook(reader, csvFileParams(filename, opts...))
+}
+
+// String 返回Excel格式的区域名称，如 A1:C2
+func (r CellRange) String() string {
+	return fmt.Sprintf("%s%d:%s%d", ColumnLetter(r.FirstColumn), r.FirstRow+1, ColumnLetter(r.LastColumn), r.LastRow+1)
+}
+
+// Contains 单元格(row, column)是否在区域内
+func (r CellRange) Contains(row, column int) bool {
+	return row >= r.FirstRow && row <= r.LastRow && column >= r.FirstColumn && column <= r.LastColumn
+}
+
+// GetMergedRanges sheet实现了 MergedSheet 时返回其合并单元格的区域，否则返回 errors.ErrUnsupported
+func GetMergedRanges(sheet Sheet) ([]CellRange, error) {
+	if ms, ok := sheet.(MergedSheet); ok {
+		return ms.MergedRanges()
+	}
+	return nil, errors.ErrUnsupported
+}
+
+// GetSheetContext workbook实现了 ContextWorkbook 时使用ctx读取sheet，否则在检查ctx后调用 Workbook.GetSheet
+func GetSheetContext(ctx context.Context, wb Workbook, index int) (Sheet, error) {
+	if cwb, ok := wb.(ContextWorkbook); ok {
+		return cwb.GetSheetContext(ctx, index)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return wb.GetSheet(index)
+}
+
+// GetSheetByNameContext workbook实现了 ContextWorkbook 时使用ctx读取sheet，否则在检查ctx后调用 Workbook.GetSheetByName
+func GetSheetByNameContext(ctx context.Context, wb Workbook, name string) (Sheet, error) {
+	if cwb, ok := wb.(ContextWorkbook); ok {
+		return cwb.GetSheetByNameContext(ctx, name)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	return wb.GetSheetByName(name)
+}
+
+// IterateSheetContext workbook实现了 ContextWorkbook 时返回检查ctx的迭代器，否则在检查ctx后调用 Workbook.IterateSheet，
+// 并在遍历时以同样的方式检查ctx
+func IterateSheetContext(ctx context.Context, wb Workbook, index int) (RowIterator, error) {
+	if cwb, ok := wb.(ContextWorkbook); ok {
+		return cwb.IterateSheetContext(ctx, index)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	it, err := wb.IterateSheet(index)
 	if err != nil {
-		return nil, fmt.Errorf("eorm: failed to open workbook: %w", err)
+		return nil, err
 	}
-	return wb, nil
+	return &contextRowIterator{RowIterator: it, ctx: ctx}, nil
+}
+
+// contextRowIterator 为不支持ctx的 RowIterator 检查ctx，行为与 ContextWorkbook.IterateSheetContext 相同
+type contextRowIterator struct {
+	RowIterator
+	ctx      context.Context
+	canceled bool
+}
+
+func (it *contextRowIterator) Next() bool {
+	if it.ctx.Err() != nil {
+		if it.canceled {
+			return false
+		}
+		it.canceled = true
+		return true
+	}
+	return it.RowIterator.Next()
+}
+
+func (it *contextRowIterator) Current() (Row, error) {
+	if err := it.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return it.RowIterator.Current()
+}
+
+// GetRowContext sheet实现了 ContextSheet 时使用ctx读取行，否则在检查ctx后调用 Sheet.GetRow
+func GetRowContext(ctx context.Context, sheet Sheet, index int) (Row, error) {
+	if cs, ok := sheet.(ContextSheet); ok {
+		return cs.GetRowContext(ctx, index)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return sheet.GetRow(index)
 }
@@ -0,0 +1,445 @@
+package eorm
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// cellRefPattern 公式中的单元格引用，引用前不能是字母、数字、下划线或者'.'，以免匹配名称中的一部分
+var cellRefPattern = regexp.MustCompile(`(^|[^A-Za-z0-9_.])(\$?[A-Za-z]{1,3})(\$?)([0-9]+)`)
+
+// FillTemplate 将objs写入xlsx模板template中名为sheetName(为空时为第一个sheet)的sheet，并将结果写入w。
+// 表头使用与 MatchTitlePath 相同的方式匹配(opts 中的 TitleStartRow、RequiredMatchLevel 等均有效，
+// 打开模板时使用 Params.WorkbookOptions)，表头的下一行为模板行：
+//   - 第一个对象写入模板行，其余对象依次写入模板行下方新插入的行，模板行之后的内容随之下移
+//   - 新行使用模板行每一列的样式，没有映射到属性的列复制模板行的公式并调整相对引用
+//   - 结束于模板行的表格、数据校验、条件格式，以及其他单元格公式中结束于模板行的区域，扩展至最后一个数据行
+//   - 通过Set方法赋值的属性与 EORM.Update 一样使用 Get<Field>() 方法的返回值，没有该方法时不写入
+//
+// objs 为空时模板不变
+func FillTemplate[T any](w io.Writer, template io.ReadSeeker, sheetName string, objs []T, opts ...Option) error {
+	params := NewParams(opts...)
+	wbParams := NewWorkbookParams(params.WorkbookOptions...)
+	wb, err := newXlsxWorkbook(template, wbParams)
+	if err != nil {
+		return err
+	}
+	f := wb.f
+	defer func() {
+		_ = f.Close()
+	}()
+	if sheetName == "" {
+		if len(wb.names) == 0 {
+			return ErrNotFound
+		}
+		sheetName = wb.names[0]
+	}
+	sheet, err := wb.GetSheetByName(sheetName)
+	if err != nil {
+		return err
+	}
+	schema, err := CompileMapperSchema(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+	mapper, tree, err := BindRowMapper[T](schema, sheet, params)
+	if err != nil {
+		return err
+	}
+	filler := &templateFiller{f: f, sheet: sheetName, row: params.MinRows(tree.Depth()) + 1, count: len(objs)}
+	if err = filler.prepare(mapper.mappedColumns()); err != nil {
+		return err
+	}
+	for i := range objs {
+		for columnIndex, v := range mapper.objectCells(&objs[i]) {
+			if err = f.SetCellValue(sheetName, filler.cell(columnIndex, i), v); err != nil {
+				return fmt.Errorf("excel/xlsx: set cell: %w", err)
+			}
+		}
+	}
+	return f.Write(w)
+}
+
+// templateFiller 在模板行(Excel行号，从1开始) row 下方插入count-1行，并复制模板行的样式和公式
+type templateFiller struct {
+	f     *excelize.File
+	sheet string
+	row   int
+	count int
+}
+
+func (t *templateFiller) cell(columnIndex, offset int) string {
+	return ColumnLetter(columnIndex) + strconv.Itoa(t.row+offset)
+}
+
+func (t *templateFiller) lastRow() int { return t.row + t.count - 1 }
+
+func (t *templateFiller) prepare(mapped map[int]bool) error {
+	if t.count <= 1 {
+		return nil
+	}
+	columns, rows, err := t.bounds()
+	if err != nil {
+		return err
+	}
+	if err = t.f.InsertRows(t.sheet, t.row+1, t.count-1); err != nil {
+		return fmt.Errorf("excel/xlsx: insert rows: %w", err)
+	}
+	// excelize插入行时已经调整了指向插入位置之后的引用，这里只扩展结束于模板行的区域
+	if err = t.extendFormulas(columns, rows+t.count-1); err != nil {
+		return err
+	}
+	for columnIndex := 0; columnIndex < columns; columnIndex++ {
+		style, err := t.f.GetCellStyle(t.sheet, t.cell(columnIndex, 0))
+		if err != nil {
+			return fmt.Errorf("excel/xlsx: get style: %w", err)
+		}
+		if style != 0 {
+			if err = t.f.SetCellStyle(t.sheet, t.cell(columnIndex, 1), t.cell(columnIndex, t.count-1), style); err != nil {
+				return fmt.Errorf("excel/xlsx: set style: %w", err)
+			}
+		}
+		if mapped[columnIndex] {
+			continue
+		}
+		formula, err := t.f.GetCellFormula(t.sheet, t.cell(columnIndex, 0))
+		if err != nil {
+			return fmt.Errorf("excel/xlsx: get formula: %w", err)
+		}
+		if formula == "" {
+			continue
+		}
+		for offset := 1; offset < t.count; offset++ {
+			if err = t.f.SetCellFormula(t.sheet, t.cell(columnIndex, offset), shiftFormulaRows(formula, offset)); err != nil {
+				return fmt.Errorf("excel/xlsx: set formula: %w", err)
+			}
+		}
+	}
+	if err = t.extendTables(); err != nil {
+		return err
+	}
+	if err = t.extendDataValidations(); err != nil {
+		return err
+	}
+	return t.extendConditionalFormats()
+}
+
+// bounds 返回sheet的列数和行数，dimension 可能不准确，所以同时检查所有的行
+func (t *templateFiller) bounds() (columns, rows int, err error) {
+	dimension, err := t.f.GetSheetDimension(t.sheet)
+	if err != nil {
+		return 0, 0, fmt.Errorf("excel/xlsx: get dimension: %w", err)
+	}
+	if _, last, _ := strings.Cut(dimension, ":"); last != "" {
+		if columns, rows, err = excelize.CellNameToCoordinates(last); err != nil {
+			return 0, 0, fmt.Errorf("excel/xlsx: %w", err)
+		}
+	}
+	all, err := t.f.GetRows(t.sheet)
+	if err != nil {
+		return 0, 0, fmt.Errorf("excel/xlsx: get rows: %w", err)
+	}
+	rows = max(rows, len(all))
+	for _, row := range all {
+		columns = max(columns, len(row))
+	}
+	return columns, rows, nil
+}
+
+// extendFormulas 插入行之后，将模板行及新插入的行以外的公式中结束于模板行的区域扩展至最后一个数据行
+func (t *templateFiller) extendFormulas(columns, rows int) error {
+	for y := 1; y <= rows; y++ {
+		if y >= t.row && y <= t.lastRow() {
+			continue
+		}
+		for x := 0; x < columns; x++ {
+			cell := ColumnLetter(x) + strconv.Itoa(y)
+			formula, err := t.f.GetCellFormula(t.sheet, cell)
+			if err != nil {
+				return fmt.Errorf("excel/xlsx: get formula: %w", err)
+			}
+			if formula == "" {
+				continue
+			}
+			if extended := extendFormulaRanges(formula, t.row, t.lastRow()); extended != formula {
+				if err = t.f.SetCellFormula(t.sheet, cell, extended); err != nil {
+					return fmt.Errorf("excel/xlsx: set formula: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// extendTables 扩展结束于模板行的表格
+func (t *templateFiller) extendTables() error {
+	tables, err := t.f.GetTables(t.sheet)
+	if err != nil {
+		return fmt.Errorf("excel/xlsx: get tables: %w", err)
+	}
+	for _, table := range tables {
+		ref, ok := extendRangeRef(table.Range, t.row, t.lastRow())
+		if !ok {
+			continue
+		}
+		if err = t.f.DeleteTable(table.Name); err != nil {
+			return fmt.Errorf("excel/xlsx: delete table: %w", err)
+		}
+		table.Range = ref
+		if err = t.f.AddTable(t.sheet, &table); err != nil {
+			return fmt.Errorf("excel/xlsx: add table: %w", err)
+		}
+	}
+	return nil
+}
+
+// extendDataValidations 扩展结束于模板行的数据校验
+func (t *templateFiller) extendDataValidations() error {
+	dvs, err := t.f.GetDataValidations(t.sheet)
+	if err != nil {
+		return fmt.Errorf("excel/xlsx: get data validations: %w", err)
+	}
+	for _, dv := range dvs {
+		sqref, ok := extendSqref(dv.Sqref, t.row, t.lastRow())
+		if !ok {
+			continue
+		}
+		if err = t.f.DeleteDataValidation(t.sheet, dv.Sqref); err != nil {
+			return fmt.Errorf("excel/xlsx: delete data validation: %w", err)
+		}
+		dv.Sqref = sqref
+		if err = t.f.AddDataValidation(t.sheet, dv); err != nil {
+			return fmt.Errorf("excel/xlsx: add data validation: %w", err)
+		}
+	}
+	return nil
+}
+
+// extendConditionalFormats 扩展结束于模板行的条件格式
+func (t *templateFiller) extendConditionalFormats() error {
+	formats, err := t.f.GetConditionalFormats(t.sheet)
+	if err != nil {
+		return fmt.Errorf("excel/xlsx: get conditional formats: %w", err)
+	}
+	for sqref, opts := range formats {
+		extended, ok := extendSqref(sqref, t.row, t.lastRow())
+		if !ok {
+			continue
+		}
+		if err = t.f.UnsetConditionalFormat(t.sheet, sqref); err != nil {
+			return fmt.Errorf("excel/xlsx: unset conditional format: %w", err)
+		}
+		if err = t.f.SetConditionalFormat(t.sheet, extended, opts); err != nil {
+			return fmt.Errorf("excel/xlsx: set conditional format: %w", err)
+		}
+	}
+	return nil
+}
+
+// extendSqref 扩展以空格分隔的多个区域中结束于row的区域
+func extendSqref(sqref string, row, lastRow int) (string, bool) {
+	refs := strings.Fields(sqref)
+	changed := false
+	for i, ref := range refs {
+		if extended, ok := extendRangeRef(ref, row, lastRow); ok {
+			refs[i], changed = extended, true
+		}
+	}
+	return strings.Join(refs, " "), changed
+}
+
+// extendRangeRef 区域(或单元格)结束于row时，扩展至lastRow
+func extendRangeRef(ref string, row, lastRow int) (string, bool) {
+	first, last, isRange := strings.Cut(ref, ":")
+	if !isRange {
+		last = first
+	}
+	x1, y1, err := excelize.CellNameToCoordinates(strings.ReplaceAll(first, "$", ""))
+	if err != nil {
+		return ref, false
+	}
+	x2, y2, err := excelize.CellNameToCoordinates(strings.ReplaceAll(last, "$", ""))
+	if err != nil || y2 != row || y1 > row {
+		return ref, false
+	}
+	ret, err := excelize.CoordinatesToCellName(x1, y1)
+	if err != nil {
+		return ref, false
+	}
+	end, err := excelize.CoordinatesToCellName(x2, lastRow)
+	if err != nil {
+		return ref, false
+	}
+	return ret + ":" + end, true
+}
+
+// shiftFormulaRows 公式中非绝对行引用的行号增加offset
+func shiftFormulaRows(formula string, offset int) string {
+	return replaceFormulaRefs(formula, func(col, abs string, row int, _ bool) string {
+		if abs == "" {
+			row += offset
+		}
+		return col + abs + strconv.Itoa(row)
+	})
+}
+
+// extendFormulaRanges 公式中结束于row的区域扩展至lastRow，例如 SUM(C5:C5) -> SUM(C5:C9)
+func extendFormulaRanges(formula string, row, lastRow int) string {
+	return replaceFormulaRefs(formula, func(col, abs string, r int, rangeEnd bool) string {
+		if rangeEnd && r == row {
+			r = lastRow
+		}
+		return col + abs + strconv.Itoa(r)
+	})
+}
+
+// replaceFormulaRefs 替换公式中(引号之外)的单元格引用，函数名(如LOG10)不会被替换。
+// rangeEnd 表示该引用是区域的结束单元格，并且区域的开始行不大于结束行
+func replaceFormulaRefs(formula string, replace func(col, abs string, row int, rangeEnd bool) string) string {
+	var sb strings.Builder
+	for len(formula) > 0 {
+		quote := strings.IndexAny(formula, `"'`)
+		if quote < 0 {
+			quote = len(formula)
+		}
+		sb.WriteString(replaceRefs(formula[:quote], replace))
+		formula = formula[quote:]
+		if len(formula) == 0 {
+			break
+		}
+		end := strings.IndexByte(formula[1:], formula[0])
+		if end < 0 {
+			sb.WriteString(formula)
+			break
+		}
+		sb.WriteString(formula[:end+2])
+		formula = formula[end+2:]
+	}
+	return sb.String()
+}
+
+func replaceRefs(s string, replace func(col, abs string, row int, rangeEnd bool) string) string {
+	var sb strings.Builder
+	last, prevRow := 0, -1
+	for _, m := range cellRefPattern.FindAllStringSubmatchIndex(s, -1) {
+		end := m[1]
+		if end < len(s) && (s[end] == '(' || isNameChar(s[end])) {
+			// 函数名或者名称
+			continue
+		}
+		row, err := strconv.Atoi(s[m[8]:m[9]])
+		if err != nil {
+			continue
+		}
+		start := m[4]
+		rangeEnd := m[2] == start-1 && s[m[2]] == ':' && last == m[2] && prevRow >= 0 && prevRow <= row
+		sb.WriteString(s[last:start])
+		sb.WriteString(replace(s[m[4]:m[5]], s[m[6]:m[7]], row, rangeEnd))
+		last, prevRow = end, row
+	}
+	sb.WriteString(s[last:])
+	return sb.String()
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// mappedColumns 所有映射到属性的列
+func (m *RowMapper[T]) mappedColumns() map[int]bool {
+	ret := make(map[int]bool)
+	for _, columnIndexes := range m.columns {
+		for _, columnIndex := range columnIndexes {
+			ret[columnIndex] = true
+		}
+	}
+	return ret
+}
+
+// objectCells 返回obj中需要写入的单元格的值，columnIndex -> value。slice属性的元素依次写入对应的各列，
+// nil指针及slice中不存在的元素对应的值为nil。
+// 通过Set方法赋值的属性，其值与单元格的值可能不同，所以使用返回值类型与Set方法参数类型相同的 Get<Field>() 方法的返回值，
+// 没有该方法时不写入(对应的单元格保持不变)
+func (m *RowMapper[T]) objectCells(obj *T) map[int]any {
+	ret := make(map[int]any)
+	ptr := reflect.ValueOf(obj)
+	for fieldIndex, columnIndexes := range m.columns {
+		cm := m.fields[fieldIndex]
+		fv, ok := cm.cellSource(ptr)
+		if !ok {
+			continue
+		}
+		slice := cm.mappingType.IsSlice()
+		for k, columnIndex := range columnIndexes {
+			if k == 0 || slice {
+				ret[columnIndex] = fieldCellValue(fv, slice, k)
+			}
+		}
+	}
+	return ret
+}
+
+// cellSource 返回写入单元格的属性值，ptr为指向对象的指针。通过Set方法赋值的属性返回 Get 方法的返回值，没有 Get 方法时返回false
+func (c *ColumnMapper) cellSource(ptr reflect.Value) (reflect.Value, bool) {
+	if !c.HasSetter {
+		return ptr.Elem().Field(c.fieldIndex), true
+	}
+	if !c.HasGetter {
+		return reflect.Value{}, false
+	}
+	return c.Getter.Func.Call([]reflect.Value{ptr})[0], true
+}
+
+// fieldCellValue 返回属性值(slice属性为第element个元素)对应的单元格的值，没有值时返回nil
+func fieldCellValue(fv reflect.Value, slice bool, element int) any {
+	if slice && fv.Kind() == reflect.Slice {
+		if element >= fv.Len() {
+			return nil
+		}
+		fv = fv.Index(element)
+	}
+	v, _ := cellValue(fv)
+	return v
+}
+
+// cellValue 将属性值转换为可以写入单元格的值
+func cellValue(v reflect.Value) (any, bool) {
+	if !v.IsValid() || ((v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil()) {
+		return nil, false
+	}
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case time.Time:
+			return x, true
+		case *time.Time:
+			return *x, true
+		}
+	}
+	e := reflect.Indirect(v)
+	switch e.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return e.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return e.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return e.Float(), true
+	case reflect.Bool:
+		return e.Bool(), true
+	case reflect.String:
+		return e.String(), true
+	}
+	if !v.CanInterface() {
+		return nil, false
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String(), true
+	}
+	return fmt.Sprint(v.Interface()), true
+}
@@ -0,0 +1,169 @@
+package eorm
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type fillObj struct {
+	Name   string   `eorm:"报表/名称"`
+	Price  float64  `eorm:"报表/单价"`
+	Amount int64    `eorm:"报表/数量"`
+	Tags   []string `eorm:"报表/标签"`
+}
+
+// newFillTemplate 第1行为标题，第2、3行为表头(包含一个不映射的"金额"列)，第4行为模板行，第5行为合计，
+// 合计行中的G5引用了模板行之后的B8
+func newFillTemplate(t *testing.T) []byte {
+	f := excelize.NewFile()
+	defer func() {
+		_ = f.Close()
+	}()
+	sheet := "Sheet1"
+	cells := map[string]any{
+		"A1": "销售报表",
+		"A2": "报表", "A3": "名称", "B3": "单价", "C3": "数量", "D3": "金额", "E3": "标签", "F3": "标签",
+		"A5": "合计", "B8": 100,
+	}
+	for cell, v := range cells {
+		if err := f.SetCellValue(sheet, cell, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.SetCellFormula(sheet, "D4", "B4*C4"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetCellFormula(sheet, "D5", "SUM(D4:D4)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetCellFormula(sheet, "G5", "SUM(C4:C4)+B8"); err != nil {
+		t.Fatal(err)
+	}
+	style, err := f.NewStyle(&excelize.Style{NumFmt: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.SetCellStyle(sheet, "B4", "B4", style); err != nil {
+		t.Fatal(err)
+	}
+	dv := excelize.NewDataValidation(true)
+	dv.Sqref = "C4"
+	if err = dv.SetRange(0, 1000, excelize.DataValidationTypeWhole, excelize.DataValidationOperatorBetween); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.AddDataValidation(sheet, dv); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.AddTable(sheet, &excelize.Table{Range: "A3:D4", Name: "Sales", StyleName: "TableStyleMedium2"}); err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err = f.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFillTemplate(t *testing.T) {
+	objs := []fillObj{
+		{Name: "苹果", Price: 3.5, Amount: 10, Tags: []string{"水果", "红色"}},
+		{Name: "香蕉", Price: 2, Amount: 20, Tags: []string{"水果"}},
+		{Name: "白菜", Price: 1.25, Amount: 4},
+	}
+	buf := new(bytes.Buffer)
+	if err := FillTemplate(buf, bytes.NewReader(newFillTemplate(t)), "", objs, WithTitleStartRow(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	sheet := "Sheet1"
+	// 结束于模板行的区域被扩展，模板行之后的引用随插入的行下移
+	formulas := map[string]string{"D4": "B4*C4", "D5": "B5*C5", "D6": "B6*C6", "D7": "SUM(D4:D6)", "G7": "SUM(C4:C6)+B10"}
+	for cell, want := range formulas {
+		if got, err := f.GetCellFormula(sheet, cell); err != nil || got != want {
+			t.Fatalf("formula of %s: %q %v, want %q", cell, got, err, want)
+		}
+	}
+	if v, _ := f.GetCellValue(sheet, "A7"); v != "合计" {
+		t.Fatalf("footer should be moved to row 7, got %q", v)
+	}
+	style4, _ := f.GetCellStyle(sheet, "B4")
+	style6, _ := f.GetCellStyle(sheet, "B6")
+	if style4 == 0 || style4 != style6 {
+		t.Fatalf("styles of the template row should be copied: %d %d", style4, style6)
+	}
+	tables, err := f.GetTables(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 || tables[0].Range != "A3:D6" || tables[0].StyleName != "TableStyleMedium2" {
+		t.Fatalf("tables %+v", tables)
+	}
+	dvs, err := f.GetDataValidations(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dvs) != 1 || dvs[0].Sqref != "C4:C6" {
+		t.Fatalf("data validations %+v", dvs)
+	}
+
+	wb, err := NewWorkbookByReadSeeker("filled.xlsx", bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	s, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := Open[fillObj](s, WithTitleStartRow(1), WithIgnoreOutOfRange())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []fillObj
+	for obj, err := range e.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, *obj)
+	}
+	// 合计行及其后的行也会被转换，slice中缺少的元素转换为空字符串
+	if len(got) != 7 || !reflect.DeepEqual(got[0], objs[0]) || got[6].Price != 100 {
+		t.Fatalf("got %+v", got)
+	}
+	for i := 1; i < len(objs); i++ {
+		if got[i].Name != objs[i].Name || got[i].Price != objs[i].Price || got[i].Amount != objs[i].Amount {
+			t.Fatalf("row %d: got %+v, want %+v", i, got[i], objs[i])
+		}
+	}
+}
+
+func TestFormulaRefs(t *testing.T) {
+	tests := []struct {
+		formula, shifted, extended string
+	}{
+		{"B4*C4", "B6*C6", "B4*C4"},
+		{"SUM(D4:D4)+$A$4+LOG10(A$4)", "SUM(D6:D6)+$A$4+LOG10(A$4)", "SUM(D4:D9)+$A$4+LOG10(A$4)"},
+		{`IF(A4="B4",'C4 sheet'!A4,Rate2024)`, `IF(A6="B4",'C4 sheet'!A6,Rate2024)`, `IF(A4="B4",'C4 sheet'!A4,Rate2024)`},
+		{"SUM(D2:D4)", "SUM(D4:D6)", "SUM(D2:D9)"},
+	}
+	for _, test := range tests {
+		if got := shiftFormulaRows(test.formula, 2); got != test.shifted {
+			t.Errorf("shift %q: %q, want %q", test.formula, got, test.shifted)
+		}
+		if got := extendFormulaRanges(test.formula, 4, 9); got != test.extended {
+			t.Errorf("extend %q: %q, want %q", test.formula, got, test.extended)
+		}
+	}
+}
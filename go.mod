@@ -3,11 +3,16 @@ module github.com/stephenfire/go-eorm
 go 1.24
 
 require (
+	github.com/mozillazg/go-pinyin v0.21.0
+	github.com/richardlehane/mscfb v1.0.4
 	github.com/shakinm/xlsReader v0.9.12
 	github.com/stephenfire/go-common v1.0.1
 	github.com/stephenfire/go-tools v0.1.0
 	github.com/urfave/cli/v2 v2.27.7
 	github.com/xuri/excelize/v2 v2.9.1
+	golang.org/x/text v0.25.0
+	golang.org/x/tools v0.33.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -18,7 +23,6 @@ require (
 	github.com/lestrrat-go/strftime v1.0.6 // indirect
 	github.com/metakeule/fmtdate v1.1.2 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/richardlehane/mscfb v1.0.4 // indirect
 	github.com/richardlehane/msoleps v1.0.4 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/sirupsen/logrus v1.9.3 // indirect
@@ -28,9 +32,10 @@ require (
 	github.com/xuri/efp v0.0.1 // indirect
 	github.com/xuri/nfp v0.0.1 // indirect
 	golang.org/x/crypto v0.38.0 // indirect
+	golang.org/x/mod v0.24.0 // indirect
 	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/sync v0.14.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/text v0.25.0 // indirect
 )
 
 replace (
@@ -0,0 +1,180 @@
+package eorm
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Limits 解析不可信文件时的资源限制，所有值为0时表示不做限制。
+//
+// * MaxRows: sheet的最大行数(包括表头)，末尾的空行不计算在内
+// * MaxColumns: 每一行的最大列数
+// * MaxCellBytes: 单元格内容的最大字节数
+// * MaxUnzipSize: zip格式(xlsx/ods)解压后的最大总字节数，对xls/csv无效
+// * Timeout: 从打开workbook(或创建EORM)开始，解析允许使用的最长时间。
+// 打开workbook时还可以通过 WithWorkbookContext 提供ctx，其deadline与Timeout中较早的一个生效，ctx被取消时同样停止解析；
+// EORM 的遍历使用 NextContext 等方法的ctx
+type Limits struct {
+	MaxRows      int
+	MaxColumns   int
+	MaxCellBytes int
+	MaxUnzipSize int64
+	Timeout      time.Duration
+}
+
+var (
+	ErrTooManyRows       = errors.New("excel: too many rows")
+	ErrTooManyColumns    = errors.New("excel: too many columns")
+	ErrCellTooLarge      = errors.New("excel: cell too large")
+	ErrUnzipSizeExceeded = errors.New("excel: unzip size exceeded")
+	ErrParseTimeout      = errors.New("excel: parse timeout")
+)
+
+// limiter 在 Limits 的基础上记录了超时的时间点，以及打开workbook时提供的ctx
+type limiter struct {
+	Limits
+	deadline time.Time
+	ctx      context.Context
+}
+
+func (l Limits) IsZero() bool { return l == Limits{} }
+
+func (l Limits) start() *limiter {
+	lm := &limiter{Limits: l}
+	if l.Timeout > 0 {
+		lm.deadline = time.Now().Add(l.Timeout)
+	}
+	return lm
+}
+
+// startContext 与 start 相同，ctx的deadline早于Timeout时以ctx为准
+func (l Limits) startContext(ctx context.Context) *limiter {
+	lm := l.start()
+	if ctx == nil {
+		return lm
+	}
+	lm.ctx = ctx
+	if d, ok := ctx.Deadline(); ok && (lm.deadline.IsZero() || d.Before(lm.deadline)) {
+		lm.deadline = d
+	}
+	return lm
+}
+
+func (l *limiter) checkDeadline() error {
+	if l == nil {
+		return nil
+	}
+	if !l.deadline.IsZero() && !time.Now().Before(l.deadline) {
+		return fmt.Errorf("%w: deadline %s exceeded", ErrParseTimeout, l.deadline.Format(time.RFC3339Nano))
+	}
+	if l.ctx != nil {
+		if err := l.ctx.Err(); errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %w", ErrParseTimeout, err)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRows rows 为当前已有的行数
+func (l *limiter) checkRows(rows int) error {
+	if l == nil || l.MaxRows <= 0 || rows <= l.MaxRows {
+		return nil
+	}
+	return fmt.Errorf("%w: exceeds %d", ErrTooManyRows, l.MaxRows)
+}
+
+func (l *limiter) checkColumns(columns int) error {
+	if l == nil || l.MaxColumns <= 0 || columns <= l.MaxColumns {
+		return nil
+	}
+	return fmt.Errorf("%w: %d columns exceeds %d", ErrTooManyColumns, columns, l.MaxColumns)
+}
+
+func (l *limiter) checkCell(cell string) error {
+	if l == nil || l.MaxCellBytes <= 0 || len(cell) <= l.MaxCellBytes {
+		return nil
+	}
+	return fmt.Errorf("%w: %d bytes exceeds %d", ErrCellTooLarge, len(cell), l.MaxCellBytes)
+}
+
+// checkRow 检查行的列数和每一个单元格的大小，以及是否超时
+func (l *limiter) checkRow(row Row) error {
+	if l == nil {
+		return nil
+	}
+	if err := l.checkDeadline(); err != nil {
+		return err
+	}
+	if err := l.checkColumns(row.ColumnCount()); err != nil {
+		return err
+	}
+	if l.MaxCellBytes > 0 {
+		for _, cell := range row.AllColumns() {
+			if err := l.checkCell(cell); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRowAt row为nil时检查行数和超时，否则检查第rowIndex行的列数和单元格大小
+func (l *limiter) checkRowAt(rowIndex int, row Row) error {
+	if row == nil {
+		if err := l.checkDeadline(); err != nil {
+			return err
+		}
+		return l.checkRows(rowIndex + 1)
+	}
+	if err := l.checkRow(row); err != nil {
+		return fmt.Errorf("eorm: row %d: %w", rowIndex, err)
+	}
+	return nil
+}
+
+func (l *limiter) checkStrings(cols []string) error {
+	if l == nil {
+		return nil
+	}
+	if err := l.checkColumns(len(cols)); err != nil {
+		return err
+	}
+	for _, cell := range cols {
+		if err := l.checkCell(cell); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkZip 根据zip目录中记录的解压后大小检查总大小，archive/zip 在读取时会校验实际大小与记录的一致
+func (l *limiter) checkZip(ra io.ReaderAt, size int64) error {
+	if l == nil || l.MaxUnzipSize <= 0 {
+		return nil
+	}
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return err
+	}
+	return l.checkZipFiles(zr.File)
+}
+
+func (l *limiter) checkZipFiles(files []*zip.File) error {
+	if l == nil || l.MaxUnzipSize <= 0 {
+		return nil
+	}
+	var total uint64
+	for _, f := range files {
+		total += f.UncompressedSize64
+		if total > uint64(l.MaxUnzipSize) {
+			return fmt.Errorf("%w: exceeds %d bytes", ErrUnzipSizeExceeded, l.MaxUnzipSize)
+		}
+	}
+	return nil
+}
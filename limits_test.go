@@ -0,0 +1,204 @@
+package eorm
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWorkbookLimits(t *testing.T) {
+	tests := []struct {
+		file   string
+		limits Limits
+		err    error
+	}{
+		{file: "title.xlsx", limits: Limits{MaxRows: 4}, err: ErrTooManyRows},
+		{file: "title.xlsx", limits: Limits{MaxColumns: 7}, err: ErrTooManyColumns},
+		{file: "title.xlsx", limits: Limits{MaxCellBytes: 8}, err: ErrCellTooLarge},
+		{file: "title.xlsx", limits: Limits{MaxUnzipSize: 1024}, err: ErrUnzipSizeExceeded},
+		{file: "title.xlsx", limits: Limits{Timeout: time.Nanosecond}, err: ErrParseTimeout},
+		{file: "title.xls", limits: Limits{MaxRows: 3}, err: ErrTooManyRows},
+		{file: "title.xls", limits: Limits{MaxColumns: 7}, err: ErrTooManyColumns},
+		{file: "title.xls", limits: Limits{MaxCellBytes: 8}, err: ErrCellTooLarge},
+		{file: "title.ods", limits: Limits{MaxRows: 4}, err: ErrTooManyRows},
+		{file: "title.ods", limits: Limits{MaxColumns: 7}, err: ErrTooManyColumns},
+		{file: "title.ods", limits: Limits{MaxUnzipSize: 1024}, err: ErrUnzipSizeExceeded},
+		{file: "title.csv", limits: Limits{MaxRows: 4}, err: ErrTooManyRows},
+		{file: "title.csv", limits: Limits{MaxCellBytes: 8}, err: ErrCellTooLarge},
+	}
+	for _, test := range tests {
+		err := func() error {
+			wb, err := NewWorkbook(filepath.Join("testdata", test.file), WithWorkbookLimits(test.limits))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = wb.Close()
+			}()
+			sheet, err := wb.GetSheet(0)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < sheet.RowCount(); i++ {
+				if _, err = sheet.GetRow(i); err != nil {
+					return err
+				}
+			}
+			return nil
+		}()
+		if !errors.Is(err, test.err) {
+			t.Fatalf("%s %+v: expecting %v, got %v", test.file, test.limits, test.err, err)
+		}
+		t.Logf("%s %+v: %v", test.file, test.limits, err)
+
+		// 不超过限制时正常读取
+		wb, err := NewWorkbook(filepath.Join("testdata", test.file), WithWorkbookLimits(Limits{
+			MaxRows: 5, MaxColumns: 8, MaxCellBytes: 64, MaxUnzipSize: 1 << 20, Timeout: time.Minute}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rangeTest(t, wb)
+		_ = wb.Close()
+	}
+}
+
+// odsBomb 生成一个只包含一个单元格，但通过重复次数展开为巨大表格的ods
+func odsBomb(t *testing.T, rows, columns int) []byte {
+	content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+<office:body><office:spreadsheet><table:table table:name="bomb">
+<table:table-row table:number-rows-repeated="%d">
+<table:table-cell table:number-columns-repeated="%d" office:value-type="string"><text:p>x</text:p></table:table-cell>
+</table:table-row>
+</table:table></office:spreadsheet></office:body></office:document-content>`, rows, columns)
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create(odsContentXml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err = zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOdsRepeatedBomb(t *testing.T) {
+	_, err := NewOdsWorkbookByReadSeeker(bytes.NewReader(odsBomb(t, 1, 1000000000)),
+		WithWorkbookLimits(Limits{MaxColumns: 16384}))
+	if !errors.Is(err, ErrTooManyColumns) {
+		t.Fatalf("ErrTooManyColumns expected, got %v", err)
+	}
+	_, err = NewOdsWorkbookByReadSeeker(bytes.NewReader(odsBomb(t, 1000000000, 10)),
+		WithWorkbookLimits(Limits{MaxRows: 1000}))
+	if !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("ErrTooManyRows expected, got %v", err)
+	}
+	wb, err := NewOdsWorkbookByReadSeeker(bytes.NewReader(odsBomb(t, 3, 4)),
+		WithWorkbookLimits(Limits{MaxRows: 3, MaxColumns: 4}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := wb.GetSheetByName("bomb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sheet.RowCount() != 3 {
+		t.Fatalf("expecting 3 rows, got %d", sheet.RowCount())
+	}
+}
+
+func TestEORMLimits(t *testing.T) {
+	wb, err := NewWorkbook(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	sheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		limits Limits
+		rows   int
+		err    error
+	}{
+		{limits: Limits{MaxRows: 4}, rows: 1, err: ErrTooManyRows},
+		{limits: Limits{MaxColumns: 7}, rows: 0, err: ErrTooManyColumns},
+		{limits: Limits{MaxCellBytes: 5}, rows: 0, err: ErrCellTooLarge},
+		{limits: Limits{MaxRows: 5, MaxColumns: 8, MaxCellBytes: 6}, rows: 2, err: nil},
+	}
+	for _, test := range tests {
+		em, err := NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj1{}), WithLimits(test.limits), WithIgnoreReadRowError())
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := 0
+		for _, err := range em.All() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows++
+		}
+		if rows != test.rows || !errors.Is(em.LastError(), test.err) {
+			t.Fatalf("%+v: expecting %d rows and %v, got %d rows and %v", test.limits, test.rows, test.err, rows, em.LastError())
+		}
+	}
+}
+
+func TestWorkbookContext(t *testing.T) {
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	canceled, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	for _, file := range []string{"title.xlsx", "title.xls", "title.ods", "title.csv"} {
+		// ctx的deadline早于 Limits.Timeout 时以ctx为准
+		_, err := NewWorkbookContext(expired, filepath.Join("testdata", file), WithWorkbookLimits(Limits{Timeout: time.Hour}))
+		if !errors.Is(err, ErrParseTimeout) {
+			t.Fatalf("%s: ErrParseTimeout expected, got %v", file, err)
+		}
+		if _, err = NewWorkbookContext(canceled, filepath.Join("testdata", file)); !errors.Is(err, context.Canceled) {
+			t.Fatalf("%s: context.Canceled expected, got %v", file, err)
+		}
+		wb, err := NewWorkbookContext(context.Background(), filepath.Join("testdata", file))
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		_ = wb.Close()
+	}
+}
+
+func TestEncryptedUnzipLimit(t *testing.T) {
+	f, err := excelize.OpenFile(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if _, err = f.WriteTo(buf, excelize.Options{Password: "password"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	// 解密后检查zip的解压大小
+	_, err = OpenWorkbook(bytes.NewReader(buf.Bytes()), WithPassword("password"), WithWorkbookLimits(Limits{MaxUnzipSize: 1024}))
+	if !errors.Is(err, ErrUnzipSizeExceeded) {
+		t.Fatalf("ErrUnzipSizeExceeded expected, got %v", err)
+	}
+	wb, err := OpenWorkbook(bytes.NewReader(buf.Bytes()), WithPassword("password"), WithWorkbookLimits(Limits{MaxUnzipSize: 1 << 20}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = wb.Close()
+}
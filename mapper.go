@@ -3,9 +3,12 @@ package eorm
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 )
 
 type (
@@ -21,6 +24,8 @@ type (
 		constraint  Constraint     // "" or required or not_null
 		Setter      reflect.Method // 对应的 Set 方法
 		HasSetter   bool           // 是否存在对应的 Set 方法
+		Getter      reflect.Method // 存在Set方法时，返回值类型与其参数类型相同的 Get 方法，用于写回单元格
+		HasGetter   bool           // 是否存在对应的 Get 方法
 	}
 
 	// RowMapper RowMapper[T]对象的主要功能是把Row转换为一个类型为*T的对象。其中：
@@ -46,16 +51,47 @@ type (
 	// 4. 当ColumnMapper.HasSetter==false时，将fieldValue直接赋值给rowData对应index为fieldIndex的属性
 	// 5. 当ColumnMapper.HasSetter==true时，将fieldValue传递给rowData对象对应的ColumnMapper.Setter方法，完成值设置。
 	// 6. 返回新创建的rowData
+	//
+	// RowMapper 创建后不再被修改，所以 Transit 可以被多个goroutine并发调用。此时Row的实现需要支持并发读取(本包中的实现均满足)，
+	// 而 Setter 方法只能修改其接收者，不能修改共享的状态。
 	RowMapper[T any] struct {
+		rowBinding
+		// 注册了 RowDecoder 时使用decoder转换
+		decoder RowDecoder[T]
+	}
+
+	// rowBinding schema与一个sheet表头匹配的结果，由 bindSchema 创建。与类型参数无关，
+	// 所以也用于对象类型在运行时才确定的场景(如 DecodeWorkbook)
+	rowBinding struct {
+		schema *MapperSchema
 		typ    reflect.Type
 		params *Params
 		// fieldIndex -> *ColumnMapper
 		fields map[int]*ColumnMapper
 		// fieldIndex -> mapping column indexes
 		columns map[int][]int
+		// columns中所有的fieldIndex(升序)，按属性的顺序转换，使出错时返回的错误是确定的
+		fieldOrder []int
+		// 类型注册了 RowDecoder 且未设置 Params.UseReflection 时不为nil，fieldColumns为以fieldIndex为下标的columns
+		registered   *registeredDecoder
+		fieldColumns [][]int
+	}
+
+	// MapperSchema 对象类型中所有需要映射的属性(ColumnMapper)，以及由它们的title path构成的 PathTree。
+	// MapperSchema 只依赖于对象类型，与sheet无关，创建后不再被修改，所以可以在多个sheet及多个goroutine之间共享。
+	// 由 CompileMapperSchema 创建，每个类型只编译一次
+	MapperSchema struct {
+		typ reflect.Type
+		// fieldIndex -> *ColumnMapper
+		fields      map[int]*ColumnMapper
+		tree        *PathTree[int]
+		fingerprint string
 	}
 )
 
+// mapperSchemas reflect.Type -> *MapperSchema
+var mapperSchemas sync.Map
+
 const (
 	MTString MappingType = iota
 	MTInt64
@@ -220,119 +256,45 @@ func (m *ColumnMapper) SetValue(rowData reflect.Value, row Row, columnIndexes []
 	return nil
 }
 
-func colToValue[T any](fn func(index int) (T, error), index int, constraint Constraint) (reflect.Value, error) {
-	v, e := fn(index)
-	if e != nil {
-		if !constraint.NeedValue() && errors.Is(e, ErrEmptyCell) {
-			return reflect.ValueOf(v), nil
-		}
-		return reflect.Value{}, e
-	}
-	val := reflect.ValueOf(v)
-	if constraint.NeedValue() && (!val.IsValid() || val.IsZero()) {
-		return reflect.Value{}, ErrEmptyCell
+// columnValue 按照属性的 MappingType 读取index列的值，转换规则与 ColumnString 等函数一致
+func (m *ColumnMapper) columnValue(row Row, index int, params *Params) (reflect.Value, error) {
+	switch m.mappingType {
+	case MTString, MTStringSlice:
+		v, err := ColumnString(row, index, m.constraint, params)
+		return reflect.ValueOf(v), err
+	case MTInt64, MTInt64Slice:
+		v, err := ColumnInt64(row, index, m.constraint, params)
+		return reflect.ValueOf(v), err
+	case MTFloat64, MTFloat64Slice:
+		v, err := ColumnFloat64(row, index, m.constraint, params)
+		return reflect.ValueOf(v), err
+	case MTBool, MTBoolSlice:
+		v, err := ColumnBool(row, index, m.constraint, params)
+		return reflect.ValueOf(v), err
+	default:
+		return reflect.Value{}, fmt.Errorf("eorm: unsupported mapping type: %s", m.mappingType)
 	}
-	return reflect.ValueOf(v), nil
 }
 
-func (m *ColumnMapper) columnValue(getter func(Row, int) (reflect.Value, error),
-	valueType reflect.Type, row Row, columnIndex int, params *Params) (reflect.Value, error) {
-	val, err := getter(row, columnIndex)
-	if m.constraint.NeedValue() && (err != nil || !val.IsValid() || val.IsZero()) {
-		return reflect.Value{}, ErrEmptyCell
-	}
+func (m *ColumnMapper) getSingleValue(row Row, columnIndex int, params *Params) (reflect.Value, error) {
+	val, err := m.columnValue(row, columnIndex, params)
 	if err != nil {
-		if (params.IgnoreParseError && errors.Is(err, ErrParseError)) ||
-			(params.IgnoreOutOfRange && errors.Is(err, ErrOutOfRange)) {
-			return reflect.Zero(valueType), nil
-		}
 		return reflect.Value{}, err
 	}
-	if !val.IsValid() {
-		val = reflect.Zero(valueType)
-	} else if val.Type() != valueType {
-		val = val.Convert(valueType)
-	}
-	return val, nil
-}
-
-func (m *ColumnMapper) getSingleValue(row Row, columnIndex int, params *Params) (reflect.Value, error) {
-	singleMap := func(getter func(row Row, index int) (reflect.Value, error)) (reflect.Value, error) {
-		return m.columnValue(getter, m.fieldType, row, columnIndex, params)
-	}
-	switch m.mappingType {
-	case MTString:
-		return singleMap(func(row Row, index int) (reflect.Value, error) {
-			if params.TrimSpace {
-				return colToValue(func(index int) (string, error) {
-					v, err := row.GetColumn(index)
-					if err != nil {
-						return v, err
-					}
-					return strings.TrimSpace(v), nil
-				}, columnIndex, m.constraint)
-			}
-			return colToValue(row.GetColumn, columnIndex, m.constraint)
-		})
-	case MTInt64:
-		return singleMap(func(row Row, index int) (reflect.Value, error) {
-			return colToValue(row.GetInt64Column, columnIndex, m.constraint)
-		})
-	case MTFloat64:
-		return singleMap(func(row Row, index int) (reflect.Value, error) {
-			return colToValue(row.GetFloat64Column, columnIndex, m.constraint)
-		})
-	case MTBool:
-		return singleMap(func(row Row, index int) (reflect.Value, error) {
-			return colToValue(row.GetBoolColumn, columnIndex, m.constraint)
-		})
-	default:
-		return reflect.Value{}, fmt.Errorf("eorm: unsupported single value mapping type: %s", m.mappingType)
-	}
+	return val.Convert(m.fieldType), nil
 }
 
 func (m *ColumnMapper) getSliceValue(row Row, columnIndexes []int, params *Params) (reflect.Value, error) {
-	sliceMap := func(getter func(row Row, index int) (reflect.Value, error)) (reflect.Value, error) {
-		slice := reflect.MakeSlice(m.fieldType, len(columnIndexes), len(columnIndexes))
-		for i, colIdx := range columnIndexes {
-			val, err := m.columnValue(getter, m.fieldType.Elem(), row, colIdx, params)
-			if err != nil {
-				return reflect.Value{}, err
-			}
-			slice.Index(i).Set(val)
+	elemType := m.fieldType.Elem()
+	slice := reflect.MakeSlice(m.fieldType, len(columnIndexes), len(columnIndexes))
+	for i, colIdx := range columnIndexes {
+		val, err := m.columnValue(row, colIdx, params)
+		if err != nil {
+			return reflect.Value{}, err
 		}
-		return slice, nil
-	}
-
-	switch m.mappingType {
-	case MTStringSlice:
-		return sliceMap(func(row Row, index int) (reflect.Value, error) {
-			if params.TrimSpace {
-				return colToValue(func(index int) (string, error) {
-					v, err := row.GetColumn(index)
-					if err != nil {
-						return "", err
-					}
-					return strings.TrimSpace(v), nil
-				}, index, m.constraint)
-			}
-			return colToValue(row.GetColumn, index, m.constraint)
-		})
-	case MTInt64Slice:
-		return sliceMap(func(row Row, index int) (reflect.Value, error) {
-			return colToValue(row.GetInt64Column, index, m.constraint)
-		})
-	case MTFloat64Slice:
-		return sliceMap(func(row Row, index int) (reflect.Value, error) {
-			return colToValue(row.GetFloat64Column, index, m.constraint)
-		})
-	case MTBoolSlice:
-		return sliceMap(func(row Row, index int) (reflect.Value, error) {
-			return colToValue(row.GetBoolColumn, index, m.constraint)
-		})
-	default:
-		return reflect.Value{}, fmt.Errorf("eorm: unsupported slice mapping type: %s", m.mappingType)
+		slice.Index(i).Set(val.Convert(elemType))
 	}
+	return slice, nil
 }
 
 // findSetterMethod 查找对应的setter方法
@@ -354,13 +316,69 @@ func findSetterMethod(objType reflect.Type, fieldName string) (method reflect.Me
 	return reflect.Method{}, MTInvalid, nil, false
 }
 
+// findGetterMethod 查找与setter对应的 func (*T) Get<Field>() paramType 方法
+func findGetterMethod(objType reflect.Type, fieldName string, paramType reflect.Type) (reflect.Method, bool) {
+	if method, ok := reflect.PointerTo(objType).MethodByName("Get" + fieldName); ok {
+		// 接收器，没有参数，一个返回值
+		if method.Type.NumIn() == 1 && method.Type.NumOut() == 1 && method.Type.Out(0) == paramType {
+			return method, true
+		}
+	}
+	return reflect.Method{}, false
+}
+
+// ParseTag 解析eorm标签，格式为 "title/path[,constraint]"，无法识别的constraint被忽略
+func ParseTag(tag string) (TitlePath, Constraint, error) {
+	titlepathTag := tag
+	constraint := Constraint("")
+	parts := strings.SplitN(tag, ",", 2)
+	if len(parts) > 1 {
+		titlepathTag = parts[0]
+		constraint = Constraint(parts[1])
+		if !constraint.IsValid() {
+			constraint = ConstraintDefault
+		}
+	}
+	titlePath, err := TitlePath(nil).Decode(titlepathTag)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode title path: %w", err)
+	}
+	if len(titlePath) == 0 {
+		return nil, "", errors.New("invalid title path")
+	}
+	return titlePath, constraint, nil
+}
+
+// NewRowMapper 使用objType的 MapperSchema 与sheet的表头进行匹配，创建 RowMapper
 func NewRowMapper[T any](objType reflect.Type, sheet Sheet, params *Params) (*RowMapper[T], *PathTree[int], error) {
+	schema, err := CompileMapperSchema(objType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return BindRowMapper[T](schema, sheet, params)
+}
+
+// CompileMapperSchema 解析objType中所有eorm标签及对应的Setter方法，结果按类型缓存，可以并发调用
+func CompileMapperSchema(objType reflect.Type) (*MapperSchema, error) {
+	if schema, ok := mapperSchemas.Load(objType); ok {
+		return schema.(*MapperSchema), nil
+	}
+	schema, err := compileMapperSchema(objType)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := mapperSchemas.LoadOrStore(objType, schema)
+	return actual.(*MapperSchema), nil
+}
+
+func compileMapperSchema(objType reflect.Type) (*MapperSchema, error) {
 	if objType.Kind() != reflect.Struct {
-		return nil, nil, fmt.Errorf("eorm: objType must be a struct, got %s", objType.Kind())
+		return nil, fmt.Errorf("eorm: objType must be a struct, got %s", objType.Kind())
 	}
 
 	fieldsMapper := make(map[int]*ColumnMapper)
 	pTree := new(PathTree[int])
+	var signatures []FieldSignature
 	numFields := objType.NumField()
 	for i := 0; i < numFields; i++ {
 		field := objType.Field(i)
@@ -371,24 +389,9 @@ func NewRowMapper[T any](objType reflect.Type, sheet Sheet, params *Params) (*Ro
 			continue
 		}
 
-		titlepathTag := eormTag
-		constraint := ""
-		parts := strings.SplitN(eormTag, ",", 2)
-		if len(parts) > 1 {
-			titlepathTag = parts[0]
-			constraint = parts[1]
-			if !Constraint(constraint).IsValid() {
-				constraint = ""
-			}
-		}
-
-		// 解析title path
-		titlePath, err := TitlePath(nil).Decode(titlepathTag)
+		titlePath, constraint, err := ParseTag(eormTag)
 		if err != nil {
-			return nil, nil, fmt.Errorf("eorm: failed to decode title path for field %s: %w", field.Name, err)
-		}
-		if len(titlePath) == 0 {
-			return nil, nil, fmt.Errorf("eorm: invalid title path of field %s", field.Name)
+			return nil, fmt.Errorf("eorm: field %s: %w", field.Name, err)
 		}
 
 		// 检查setter方法
@@ -397,14 +400,14 @@ func NewRowMapper[T any](objType reflect.Type, sheet Sheet, params *Params) (*Ro
 		if !hasSetter {
 			mtType, err = NewMappingType(field.Type)
 			if err != nil {
-				return nil, nil, err
+				return nil, err
 			}
 		} else {
 			if paramType == nil {
-				return nil, nil, fmt.Errorf("eorm: invalid param type for field %s setter", field.Name)
+				return nil, fmt.Errorf("eorm: invalid param type for field %s setter", field.Name)
 			}
 			if !mtType.IsValid() {
-				return nil, nil, fmt.Errorf("eorm: unsupported mapping type of filed %s", field.Name)
+				return nil, fmt.Errorf("eorm: unsupported mapping type of filed %s", field.Name)
 			}
 		}
 
@@ -413,22 +416,65 @@ func NewRowMapper[T any](objType reflect.Type, sheet Sheet, params *Params) (*Ro
 			mappingType: mtType,
 			fieldName:   field.Name,
 			titlePath:   titlePath,
-			constraint:  Constraint(constraint),
+			constraint:  constraint,
 			Setter:      setterMethod,
 			HasSetter:   hasSetter,
 		}
 		if hasSetter {
 			columnMapper.fieldType = paramType
+			columnMapper.Getter, columnMapper.HasGetter = findGetterMethod(objType, field.Name, paramType)
 		} else {
 			columnMapper.fieldType = field.Type
 		}
 
 		fieldsMapper[i] = columnMapper
 		if err = pTree.Put(i, titlePath); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
+		signature := FieldSignature{Index: i, Name: field.Name, Tag: eormTag, Mapping: mtType}
+		if hasSetter {
+			signature.Setter = setterMethod.Name
+		}
+		signatures = append(signatures, signature)
 	}
 
+	return &MapperSchema{typ: objType, fields: fieldsMapper, tree: pTree, fingerprint: Fingerprint(signatures)}, nil
+}
+
+// Type 返回对象类型
+func (s *MapperSchema) Type() reflect.Type { return s.typ }
+
+// Tree 返回所有属性的title path构成的 PathTree，不能被修改
+func (s *MapperSchema) Tree() *PathTree[int] { return s.tree }
+
+// Fingerprint 返回所有映射属性的指纹，RowDecoder.Fingerprint 与之相同时才能使用该decoder
+func (s *MapperSchema) Fingerprint() string { return s.fingerprint }
+
+// BindRowMapper 将schema与sheet的表头进行匹配，创建 RowMapper。只有这一步依赖于sheet
+func BindRowMapper[T any](schema *MapperSchema, sheet Sheet, params *Params) (*RowMapper[T], *PathTree[int], error) {
+	if schema == nil {
+		return nil, nil, ErrNil
+	}
+	if typ := reflect.TypeFor[T](); schema.typ != typ {
+		return nil, nil, fmt.Errorf("eorm: schema of %s mismatch with %s", schema.typ, typ)
+	}
+	binding, pTree, err := bindSchema(schema, sheet, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	mp := &RowMapper[T]{rowBinding: *binding}
+	if binding.registered != nil {
+		mp.decoder = binding.registered.decoder.(RowDecoder[T])
+	}
+	return mp, pTree, nil
+}
+
+// bindSchema 将schema与sheet的表头进行匹配，并检查 Params.RequiredMatchLevel
+func bindSchema(schema *MapperSchema, sheet Sheet, params *Params) (*rowBinding, *PathTree[int], error) {
+	if schema == nil {
+		return nil, nil, ErrNil
+	}
+	fieldsMapper, pTree := schema.fields, schema.tree
 	// 构建 fieldIndex -> []columnIndex 的映射
 	// 1. 先从PathTree获取 columnIndex -> fieldIndex
 	columnToField, err := MatchTitlePath(pTree, sheet, params)
@@ -448,7 +494,7 @@ func NewRowMapper[T any](objType reflect.Type, sheet Sheet, params *Params) (*Ro
 		}
 		if len(columnIndexes) > 1 {
 			if !columnMapper.mappingType.IsSlice() {
-				return nil, nil, fmt.Errorf("eorm: a slice mapping type is needed for multi-columns at field index %d", fieldIndex)
+				return nil, nil, fmt.Errorf("%w: a slice mapping type is needed for multi-columns at field index %d", ErrTitleMismatch, fieldIndex)
 			}
 		}
 
@@ -464,54 +510,110 @@ func NewRowMapper[T any](objType reflect.Type, sheet Sheet, params *Params) (*Ro
 			}
 		}
 	}
-	mp := &RowMapper[T]{
-		typ:     objType,
-		params:  params,
-		fields:  fieldsMapper,
-		columns: fieldToColumns,
+	b := &rowBinding{
+		schema:     schema,
+		typ:        schema.typ,
+		params:     params,
+		fields:     fieldsMapper,
+		columns:    fieldToColumns,
+		fieldOrder: slices.Sorted(maps.Keys(fieldToColumns)),
+	}
+	if !params.UseReflection {
+		if b.registered = lookupRegistered(schema.typ); b.registered != nil {
+			b.fieldColumns = make([][]int, schema.typ.NumField())
+			for fieldIndex, columnIndexes := range fieldToColumns {
+				b.fieldColumns[fieldIndex] = columnIndexes
+			}
+		}
 	}
 	// 5. 检查 match level
 	switch params.RequiredMatchLevel.Formalize() {
 	case MatchLevelPerfect:
-		if !mp.IsPerfectMatch() {
+		if !b.IsPerfectMatch() {
 			return nil, nil, ErrInsufficientMatchLevel
 		}
 	case MatchLevelMatched:
-		if !mp.IsMatched() {
+		if !b.IsMatched() {
 			return nil, nil, ErrInsufficientMatchLevel
 		}
 	default:
 		// ok
 	}
 
-	return mp, pTree, nil
+	return b, pTree, nil
 }
 
+// Schema 返回创建该 RowMapper 所使用的 MapperSchema
+func (m *rowBinding) Schema() *MapperSchema { return m.schema }
+
 // IsPerfectMatch 对象每一个属性都找到了对应列
-func (m *RowMapper[T]) IsPerfectMatch() bool {
+func (m *rowBinding) IsPerfectMatch() bool {
 	return len(m.fields) > 0 && len(m.fields) == len(m.columns)
 }
 
 // IsMatched 对象中至少有一个属性找到了对应列
-func (m *RowMapper[T]) IsMatched() bool { return len(m.columns) > 0 }
+func (m *rowBinding) IsMatched() bool { return len(m.columns) > 0 }
+
+// orderedFields 必需属性(没有时为所有匹配的属性)按声明顺序排列时，首列下标大于前一个属性首列下标的属性数
+func (m *rowBinding) orderedFields() int {
+	var fieldIndexes []int
+	for _, fieldIndex := range m.fieldOrder {
+		if m.fields[fieldIndex].constraint.NeedMapper() {
+			fieldIndexes = append(fieldIndexes, fieldIndex)
+		}
+	}
+	if len(fieldIndexes) == 0 {
+		fieldIndexes = m.fieldOrder
+	}
+	count, last := 0, -1
+	for _, fieldIndex := range fieldIndexes {
+		if first := m.columns[fieldIndex][0]; first > last {
+			count++
+			last = first
+		}
+	}
+	return count
+}
 
+// Transit 将row转换为*T，类型T注册了 RowDecoder 时使用decoder，否则使用反射，两者的结果一致
 func (m *RowMapper[T]) Transit(row Row) (*T, error) {
 	if row == nil {
 		return nil, nil
 	}
+	if m.decoder != nil {
+		return m.decoder.DecodeRow(row, m.fieldColumns, m.params)
+	}
+	val, err := m.transitValue(row)
+	if err != nil {
+		return nil, err
+	}
+	return val.Interface().(*T), nil
+}
+
+// decodeValue 将row转换为指向 rowBinding.typ 的指针，与 RowMapper.Transit 相同，注册了 RowDecoder 时使用decoder
+func (m *rowBinding) decodeValue(row Row) (reflect.Value, error) {
+	if m.registered != nil {
+		return m.registered.decodeValue(row, m.fieldColumns, m.params)
+	}
+	return m.transitValue(row)
+}
+
+// transitValue 使用反射将row转换为指向 rowBinding.typ 的指针
+func (m *rowBinding) transitValue(row Row) (reflect.Value, error) {
 	val := reflect.New(m.typ)
 
-	for fieldIndex, columnIndexes := range m.columns {
+	for _, fieldIndex := range m.fieldOrder {
+		columnIndexes := m.columns[fieldIndex]
 		if len(columnIndexes) == 0 {
 			continue
 		}
 		columnMapper := m.fields[fieldIndex]
 		if columnMapper == nil {
-			return nil, fmt.Errorf("eorm: no column mapper found for field index %d", fieldIndex)
+			return reflect.Value{}, fmt.Errorf("eorm: no column mapper found for field index %d", fieldIndex)
 		}
 		if err := columnMapper.SetValue(val, row, columnIndexes, m.params); err != nil {
-			return nil, err
+			return reflect.Value{}, err
 		}
 	}
-	return val.Interface().(*T), nil
+	return val, nil
 }
@@ -0,0 +1,416 @@
+package eorm
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	odsMimeType   = "application/vnd.oasis.opendocument.spreadsheet"
+	odsContentXml = "content.xml"
+
+	odsNsOffice = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsNsTable  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsNsText   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+type (
+	// odsWorkbook OpenDocument Spreadsheet，打开时解析content.xml中所有sheet的内容。
+	// 被合并(covered)的单元格与xlsx一致，视为空单元格。
+	odsWorkbook struct {
+		nameMap map[string]int // name -> index
+		sheets  []*textSheet
+	}
+
+	// odsRowBuilder 展开重复的行和列。文件末尾常以很大的重复次数填充空行/空列，
+	// 所以空行和空单元格只有在其后出现有内容的行或单元格时才会真正展开。
+	odsRowBuilder struct {
+		rows         [][]string
+		pendingRows  int // 尚未展开的空行数
+		row          []string
+		pendingCells int // 尚未展开的空单元格数
+		rowRepeat    int
+		merged       []CellRange
+		limiter      *limiter
+	}
+)
+
+func NewOdsWorkbook(filePath string, opts ...WorkbookOption) (Workbook, error) {
+	lm := NewWorkbookParams(opts...).startLimits()
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("excel/ods: %w", err)
+	}
+	defer func() {
+		_ = zr.Close()
+	}()
+	return newOdsWorkbook(&zr.Reader, lm)
+}
+
+func NewOdsWorkbookByReadSeeker(reader io.ReadSeeker, opts ...WorkbookOption) (Workbook, error) {
+	lm := NewWorkbookParams(opts...).startLimits()
+	ra, size, err := toReaderAt(reader)
+	if err != nil {
+		return nil, fmt.Errorf("excel/ods: %w", err)
+	}
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, fmt.Errorf("excel/ods: %w", err)
+	}
+	return newOdsWorkbook(zr, lm)
+}
+
+// toReaderAt 将 io.ReadSeeker 转换为 zip 需要的 io.ReaderAt 及其长度
+func toReaderAt(reader io.ReadSeeker) (io.ReaderAt, int64, error) {
+	if ra, ok := reader.(io.ReaderAt); ok {
+		size, err := reader.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err = reader.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+		return ra, size, nil
+	}
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
+
+func newOdsWorkbook(zr *zip.Reader, lm *limiter) (*odsWorkbook, error) {
+	if err := lm.checkZipFiles(zr.File); err != nil {
+		return nil, fmt.Errorf("excel/ods: %w", err)
+	}
+	var content *zip.File
+	for _, f := range zr.File {
+		switch f.Name {
+		case "mimetype":
+			if err := checkOdsMimeType(f); err != nil {
+				return nil, err
+			}
+		case odsContentXml:
+			content = f
+		}
+	}
+	if content == nil {
+		return nil, fmt.Errorf("excel/ods: %s %w", odsContentXml, ErrNotFound)
+	}
+	rc, err := content.Open()
+	if err != nil {
+		return nil, fmt.Errorf("excel/ods: %w", err)
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	sheets, err := parseOdsContent(rc, lm)
+	if err != nil {
+		return nil, fmt.Errorf("excel/ods: %w", err)
+	}
+	nameMap := make(map[string]int)
+	for i, sheet := range sheets {
+		nameMap[sheet.name] = i
+	}
+	return &odsWorkbook{nameMap: nameMap, sheets: sheets}, nil
+}
+
+func checkOdsMimeType(f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("excel/ods: %w", err)
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	mt, err := io.ReadAll(io.LimitReader(rc, 256))
+	if err != nil {
+		return fmt.Errorf("excel/ods: %w", err)
+	}
+	if strings.TrimSpace(string(mt)) != odsMimeType {
+		return fmt.Errorf("excel/ods: unsupported mimetype %q", mt)
+	}
+	return nil
+}
+
+func odsAttr(se xml.StartElement, space, local string) (string, bool) {
+	for _, attr := range se.Attr {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+func odsRepeated(se xml.StartElement, local string) int {
+	v, ok := odsAttr(se, odsNsTable, local)
+	if !ok {
+		return 1
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+func parseOdsContent(r io.Reader, lm *limiter) ([]*textSheet, error) {
+	var sheets []*textSheet
+	var sheet *textSheet
+	var builder *odsRowBuilder
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != odsNsTable {
+				continue
+			}
+			switch t.Name.Local {
+			case "table":
+				name, _ := odsAttr(t, odsNsTable, "name")
+				sheet = &textSheet{format: "ods", name: name}
+				builder = &odsRowBuilder{limiter: lm}
+			case "table-row":
+				if builder != nil {
+					builder.startRow(odsRepeated(t, "number-rows-repeated"))
+				}
+			case "table-cell", "covered-table-cell":
+				if builder == nil {
+					continue
+				}
+				repeated := odsRepeated(t, "number-columns-repeated")
+				if t.Name.Local == "covered-table-cell" {
+					// 被合并的单元格，其内容被忽略
+					if err = dec.Skip(); err != nil {
+						return nil, err
+					}
+					if err = builder.addCell("", repeated); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				builder.addSpan(odsRepeated(t, "number-rows-spanned"), odsRepeated(t, "number-columns-spanned"))
+				val, err := odsCellValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				if err = builder.addCell(val, repeated); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Space != odsNsTable {
+				continue
+			}
+			switch t.Name.Local {
+			case "table":
+				if sheet != nil && builder != nil {
+					sheet.allRows = builder.rows
+					sheet.merged = builder.merged
+					sheets = append(sheets, sheet)
+				}
+				sheet, builder = nil, nil
+			case "table-row":
+				if builder != nil {
+					if err = builder.endRow(); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return sheets, nil
+}
+
+// odsCellValue 读取单元格的值并消费到单元格结束标签。
+// 数值类单元格使用 office:value 等属性保存的原始值，而非显示的格式化文本。
+func odsCellValue(dec *xml.Decoder, se xml.StartElement) (string, error) {
+	valueType, _ := odsAttr(se, odsNsOffice, "value-type")
+	var attrVal string
+	var hasAttr bool
+	switch valueType {
+	case "float", "percentage", "currency":
+		attrVal, hasAttr = odsAttr(se, odsNsOffice, "value")
+	case "boolean":
+		var b string
+		if b, hasAttr = odsAttr(se, odsNsOffice, "boolean-value"); hasAttr {
+			attrVal = strings.ToUpper(b)
+		}
+	case "date":
+		attrVal, hasAttr = odsAttr(se, odsNsOffice, "date-value")
+	case "time":
+		attrVal, hasAttr = odsAttr(se, odsNsOffice, "time-value")
+	case "string":
+		attrVal, hasAttr = odsAttr(se, odsNsOffice, "string-value")
+	}
+	text, err := odsCellText(dec)
+	if err != nil {
+		return "", err
+	}
+	if hasAttr {
+		return attrVal, nil
+	}
+	return text, nil
+}
+
+// odsCellText 读取单元格中所有 text:p 的文本，多个段落以换行连接
+func odsCellText(dec *xml.Decoder) (string, error) {
+	sb := strings.Builder{}
+	paragraphs := 0
+	inParagraph := 0 // 当前所在 text:p 的深度，0表示不在段落中
+	depth := 1
+	for depth > 0 {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if t.Name.Space == odsNsOffice && t.Name.Local == "annotation" {
+				// 批注不属于单元格内容
+				if err = dec.Skip(); err != nil {
+					return "", err
+				}
+				depth--
+				continue
+			}
+			if t.Name.Space != odsNsText {
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				if paragraphs > 0 {
+					sb.WriteByte('\n')
+				}
+				paragraphs++
+				inParagraph = depth
+			case "s":
+				n := 1
+				if c, ok := odsAttr(t, odsNsText, "c"); ok {
+					if v, err := strconv.Atoi(c); err == nil && v > 0 {
+						n = v
+					}
+				}
+				sb.WriteString(strings.Repeat(" ", n))
+			case "tab":
+				sb.WriteByte('\t')
+			case "line-break":
+				sb.WriteByte('\n')
+			}
+		case xml.EndElement:
+			if depth == inParagraph {
+				inParagraph = 0
+			}
+			depth--
+		case xml.CharData:
+			if inParagraph > 0 {
+				sb.Write(t)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+func (b *odsRowBuilder) startRow(repeated int) {
+	b.row = nil
+	b.pendingCells = 0
+	b.rowRepeat = repeated
+}
+
+// addCell 在展开重复的单元格之前检查资源限制，避免被很大的重复次数耗尽内存
+func (b *odsRowBuilder) addCell(val string, repeated int) error {
+	if val == "" {
+		b.pendingCells += repeated
+		return nil
+	}
+	if err := b.limiter.checkColumns(len(b.row) + b.pendingCells + repeated); err != nil {
+		return fmt.Errorf("row %d: %w", len(b.rows)+b.pendingRows, err)
+	}
+	if err := b.limiter.checkCell(val); err != nil {
+		return fmt.Errorf("row %d: %w", len(b.rows)+b.pendingRows, err)
+	}
+	for ; b.pendingCells > 0; b.pendingCells-- {
+		b.row = append(b.row, "")
+	}
+	for i := 0; i < repeated; i++ {
+		b.row = append(b.row, val)
+	}
+	return nil
+}
+
+// addSpan 记录由当前单元格开始的合并区域，需要在 addCell 之前调用
+func (b *odsRowBuilder) addSpan(rows, columns int) {
+	if rows <= 1 && columns <= 1 {
+		return
+	}
+	row, column := len(b.rows)+b.pendingRows, len(b.row)+b.pendingCells
+	b.merged = append(b.merged, CellRange{FirstRow: row, FirstColumn: column, LastRow: row + rows - 1, LastColumn: column + columns - 1})
+}
+
+func (b *odsRowBuilder) endRow() error {
+	if err := b.limiter.checkDeadline(); err != nil {
+		return err
+	}
+	if len(b.row) == 0 {
+		b.pendingRows += b.rowRepeat
+		return nil
+	}
+	if err := b.limiter.checkRows(len(b.rows) + b.pendingRows + b.rowRepeat); err != nil {
+		return err
+	}
+	for ; b.pendingRows > 0; b.pendingRows-- {
+		b.rows = append(b.rows, nil)
+	}
+	for i := 0; i < b.rowRepeat; i++ {
+		b.rows = append(b.rows, b.row)
+	}
+	b.row = nil
+	return nil
+}
+
+func (o *odsWorkbook) SheetCount() int {
+	return len(o.sheets)
+}
+
+func (o *odsWorkbook) GetSheet(index int) (Sheet, error) {
+	if index < 0 || index >= len(o.sheets) {
+		return nil, ErrOutOfRange
+	}
+	return o.sheets[index], nil
+}
+
+func (o *odsWorkbook) GetSheetByName(name string) (Sheet, error) {
+	idx, exist := o.nameMap[name]
+	if !exist {
+		return nil, ErrNotFound
+	}
+	return o.sheets[idx], nil
+}
+
+func (o *odsWorkbook) IterateSheet(index int) (RowIterator, error) {
+	if index < 0 || index >= len(o.sheets) {
+		return nil, ErrOutOfRange
+	}
+	return &textRowIterator{curRow: -1, sheet: o.sheets[index]}, nil
+}
+
+func (o *odsWorkbook) Close() error {
+	return nil
+}
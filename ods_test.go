@@ -0,0 +1,84 @@
+package eorm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOdsTitle(t *testing.T) {
+	wb, err := NewWorkbook(filepath.Join("testdata", "title.ods"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	if wb.SheetCount() != 2 {
+		t.Fatalf("expecting 2 sheets, got %d", wb.SheetCount())
+	}
+	sheet, err := wb.GetSheetByName("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 末尾重复的空行和空列不应被展开
+	if sheet.RowCount() != 5 {
+		t.Fatalf("expecting 5 rows, got %d", sheet.RowCount())
+	}
+	em, err := NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj1{}), WithMatchLevel(MatchLevelPerfect))
+	if err != nil {
+		t.Fatalf("NewEORM failed: %v", err)
+	}
+	testTitle1(em, t)
+
+	tps, err := BuildTitlePaths(sheet, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("title paths:\n%s", tps.Info())
+	if len(tps) != 8 || tps[7].Encode() != "第一级/第二级/第三级" {
+		t.Fatalf("unexpected title paths:\n%s", tps.Info())
+	}
+	rangeTest(t, wb)
+	iterateTest(t, wb)
+}
+
+func TestOdsText(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "title.ods"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wb, err := NewWorkbookByReadSeeker("upload.ods", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := wb.GetSheet(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectings := [][]string{
+		{"多 段", "第一段\n第  二段"},
+		nil,
+		nil,
+		{"", "", "C4"},
+	}
+	if sheet.RowCount() != len(expectings) {
+		t.Fatalf("expecting %d rows, got %d", len(expectings), sheet.RowCount())
+	}
+	for i, cols := range expectings {
+		row, err := sheet.GetRow(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row.ColumnCount() != len(cols) {
+			t.Fatalf("row %d: expecting %d columns, got %d", i, len(cols), row.ColumnCount())
+		}
+		for j, col := range cols {
+			if v, _ := row.GetColumn(j); v != col {
+				t.Fatalf("row %d column %d: expecting %q, got %q", i, j, col, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package eorm
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+type (
+	// RowResult 并发转换时一行的结果，RowIndex 为该行在sheet中的行下标
+	RowResult[T any] struct {
+		RowIndex int
+		Obj      *T
+		Err      error
+	}
+
+	parallelJob[T any] struct {
+		rowIndex int
+		row      Row
+		result   chan RowResult[T]
+	}
+)
+
+// ParallelRows 由当前的遍历位置开始，按sheet中的行顺序返回每一行的转换结果。
+// 行仍然通过 NextContext() 顺序读取，RowMapper.Transit 由workers个goroutine并发执行，workers<=0时使用 runtime.GOMAXPROCS(0)。
+// 遍历结束的原因与 Next() 一致，通过 LastError() 获取，ctx被取消时为 ctx.Err()。
+// 调用方提前结束遍历或者ctx被取消时，已预读但未返回的行被丢弃，遍历位置回到最后一个返回的行，之后的 Next()、Batches() 等从其下一行继续。
+// 遍历过程中不能再调用该EORM的其他方法。
+func (e *EORM[T]) ParallelRows(ctx context.Context, workers int) iter.Seq[RowResult[T]] {
+	return func(yield func(RowResult[T]) bool) {
+		if workers <= 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+		// 最后一个返回给调用方的行，尚未返回任何行时为开始时的遍历位置
+		resume := e.rowIndex
+		innerCtx, cancel := context.WithCancel(ctx)
+		jobs := make(chan *parallelJob[T], workers)
+		// 按读取顺序保存每一行的job，消费者按此顺序等待结果，所以输出的顺序与sheet一致
+		ordered := make(chan *parallelJob[T], workers*2)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(jobs)
+			defer close(ordered)
+			for e.NextContext(innerCtx) {
+				job := &parallelJob[T]{rowIndex: e.rowIndex, row: e.currentRow, result: make(chan RowResult[T], 1)}
+				select {
+				case ordered <- job:
+				case <-innerCtx.Done():
+					return
+				}
+				select {
+				case jobs <- job:
+				case <-innerCtx.Done():
+					return
+				}
+			}
+		}()
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					obj, err := e.rowMapper.Transit(job.row)
+					job.result <- RowResult[T]{RowIndex: job.rowIndex, Obj: obj, Err: err}
+				}
+			}()
+		}
+
+		drained := true
+		for job := range ordered {
+			var res RowResult[T]
+			select {
+			case res = <-job.result:
+			case <-innerCtx.Done():
+			}
+			if innerCtx.Err() != nil {
+				drained = false
+				break
+			}
+			resume = res.RowIndex
+			// 在调用方的goroutine中记录，与 Current() 一样用于 RowIndexOf 和 UpdateObject
+			if res.Obj != nil {
+				e.track(res.Obj, res.RowIndex)
+			}
+			if !yield(res) {
+				drained = false
+				break
+			}
+		}
+		cancel()
+		wg.Wait()
+		if !drained || (ctx.Err() != nil && e.rowIndex != -2) {
+			// 生产者可能已经预读了之后的行，回到最后一个返回的行。由调用方提前结束遍历导致的取消不是错误
+			e.rowIndex = resume
+			e.lastErr = ctx.Err()
+		}
+		e.currentRow, e.currentObj = nil, nil
+	}
+}
+
+// DecodeAll 使用 ParallelRows 并发转换剩余的所有行，结果按sheet中的行顺序排列，每一行的转换错误保存在 RowResult.Err 中。
+// 当遍历因为ctx被取消、超出 Params.Limits 或者读取行失败(未设置 IgnoreReadRowError 时)而提前结束时，返回已转换的结果及对应的错误
+func (e *EORM[T]) DecodeAll(ctx context.Context, workers int) ([]RowResult[T], error) {
+	var results []RowResult[T]
+	for res := range e.ParallelRows(ctx, workers) {
+		results = append(results, res)
+	}
+	return results, e.stopError()
+}
@@ -0,0 +1,187 @@
+package eorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type parallelObj struct {
+	Id    int64  `eorm:"序号"`
+	Name  string `eorm:"名称"`
+	Score int64  `eorm:"分数"`
+}
+
+func parallelSheet(t *testing.T, rows int) Sheet {
+	sb := strings.Builder{}
+	sb.WriteString("序号,名称,分数\n")
+	for i := 0; i < rows; i++ {
+		if i%10 == 3 {
+			// 转换出错的行
+			sb.WriteString(fmt.Sprintf("%d,name%d,NaN\n", i, i))
+		} else {
+			sb.WriteString(fmt.Sprintf("%d,name%d,%d\n", i, i, i*10))
+		}
+	}
+	wb, err := NewCsvWorkbookByReader(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sheet
+}
+
+func TestDecodeAll(t *testing.T) {
+	const rows = 1000
+	sheet := parallelSheet(t, rows)
+	em, err := NewEORM[parallelObj](sheet, reflect.TypeOf(parallelObj{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := em.DecodeAll(context.Background(), 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != rows {
+		t.Fatalf("expecting %d results, got %d", rows, len(results))
+	}
+	for i, res := range results {
+		if res.RowIndex != i+1 {
+			t.Fatalf("expecting row index %d, got %d", i+1, res.RowIndex)
+		}
+		if i%10 == 3 {
+			if !errors.Is(res.Err, ErrParseError) {
+				t.Fatalf("row %d: ErrParseError expected, got %v", res.RowIndex, res.Err)
+			}
+			continue
+		}
+		if res.Err != nil {
+			t.Fatalf("row %d: %v", res.RowIndex, res.Err)
+		}
+		if res.Obj.Id != int64(i) || res.Obj.Name != fmt.Sprintf("name%d", i) || res.Obj.Score != int64(i*10) {
+			t.Fatalf("row %d: unexpected %+v", res.RowIndex, res.Obj)
+		}
+	}
+
+	// 与顺序遍历的结果一致
+	wb, err := NewWorkbook(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	xsheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tem, err := NewEORM[TitleObj1](xsheet, reflect.TypeOf(TitleObj1{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	titleResults, err := tem.DecodeAll(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(titleResults) != len(title1Expectings) {
+		t.Fatalf("expecting %d results, got %d", len(title1Expectings), len(titleResults))
+	}
+	for i, res := range titleResults {
+		if res.Err != nil || !title1Expectings[i].Equals(res.Obj) {
+			t.Fatalf("expecting %+v, got %+v %v", title1Expectings[i], res.Obj, res.Err)
+		}
+	}
+}
+
+func TestParallelRowsStop(t *testing.T) {
+	sheet := parallelSheet(t, 1000)
+	em, err := NewEORM[parallelObj](sheet, reflect.TypeOf(parallelObj{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 提前结束遍历
+	count := 0
+	for res := range em.ParallelRows(context.Background(), 4) {
+		if res.RowIndex != count+1 {
+			t.Fatalf("expecting row index %d, got %d", count+1, res.RowIndex)
+		}
+		count++
+		if count == 100 {
+			break
+		}
+	}
+	if em.LastError() != nil {
+		t.Fatalf("no error expected, got %v", em.LastError())
+	}
+	// 预读的行没有丢失，从下一行继续
+	if !em.Next() {
+		t.Fatalf("next row expected, got %v", em.LastError())
+	}
+	if rowIndex, _ := em.CurrentRowNumber(); rowIndex != 101 {
+		t.Fatalf("expecting row index 101, got %d", rowIndex)
+	}
+	rest, err := em.DecodeAll(context.Background(), 4)
+	if err != nil || len(rest) != 899 || rest[0].RowIndex != 102 {
+		t.Fatalf("expecting 899 rows from 102, got %d %v", len(rest), err)
+	}
+
+	// ctx被取消
+	em.Reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	count = 0
+	for range em.ParallelRows(ctx, 4) {
+		count++
+		if count == 100 {
+			cancel()
+		}
+	}
+	if count < 100 || count >= 1000 {
+		t.Fatalf("unexpected count %d", count)
+	}
+	if !errors.Is(em.LastError(), context.Canceled) {
+		t.Fatalf("context.Canceled expected, got %v", em.LastError())
+	}
+	results, err := em.DecodeAll(ctx, 4)
+	if len(results) != 0 || !errors.Is(err, context.Canceled) {
+		t.Fatalf("context.Canceled expected, got %d results and %v", len(results), err)
+	}
+	results, err = em.DecodeAll(context.Background(), 4)
+	if err != nil || len(results) != 1000-count || results[0].RowIndex != count+1 {
+		t.Fatalf("expecting %d rows from %d, got %d %v", 1000-count, count+1, len(results), err)
+	}
+}
+
+// TestParallelReflection 使用反射并发转换(go test -race)，结果与顺序遍历一致
+func TestParallelReflection(t *testing.T) {
+	sheet := parallelSheet(t, 2000)
+	em, err := Open[parallelObj](sheet, WithReflection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []RowResult[parallelObj]
+	for obj, err := range em.All() {
+		want = append(want, RowResult[parallelObj]{RowIndex: em.rowIndex, Obj: obj, Err: err})
+	}
+	em.Reset()
+	got, err := em.DecodeAll(context.Background(), 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expecting %d results, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].RowIndex != want[i].RowIndex || !reflect.DeepEqual(got[i].Obj, want[i].Obj) ||
+			fmt.Sprint(got[i].Err) != fmt.Sprint(want[i].Err) {
+			t.Fatalf("row %d: expecting %+v, got %+v", want[i].RowIndex, want[i], got[i])
+		}
+	}
+}
@@ -1,5 +1,13 @@
 package eorm
 
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"golang.org/x/text/encoding"
+)
+
 type (
 	Params struct {
 		TrimSpace              bool       // 是否删除首尾空格，缺省不删除
@@ -10,11 +18,37 @@ type (
 		GenLastRowNoMerged     bool       // 生成TitlePath时，最后一行的空不认为是横向合并
 		TitleStartRow          int        // 从哪一行(行号从0开始)开始分析title_path, 所有小于0的值均被认为是0
 		RequiredMatchLevel     MatchLevel // 需要类型与excel表头的匹配程度，无论何值，tag.constraint的要求必须达成
+		Limits                 Limits     // 遍历数据行时的资源限制，超时时间从创建EORM开始计算
+		UseReflection          bool       // 即使类型注册了 RowDecoder，仍然使用反射转换
+		TrackRows              bool       // 记录每个对象来源的行下标，用于 EORM.RowIndexOf 和 EORM.UpdateObject
+
+		WorkbookOptions []WorkbookOption    // ReadAll 等辅助函数打开workbook时使用的参数
+		SheetOptions    map[string][]Option // DecodeWorkbook 中按 eorm_sheet 标签的值为对应的sheet追加的参数
 	}
 
 	Option func(p *Params)
 
 	MatchLevel byte
+
+	// WorkbookParams 打开 Workbook 时使用的参数
+	WorkbookParams struct {
+		SheetName string            // 单sheet格式(csv/tsv)的sheet名称，缺省时使用文件名(不含扩展名)，无文件名时为"Sheet1"
+		Comma     rune              // csv 字段分隔符，缺省为','，.tsv文件缺省为'\t'
+		Comment   rune              // csv 注释行的起始字符，0表示不支持注释
+		Quoting   CsvQuoting        // csv 引号处理方式
+		Encoding  encoding.Encoding // csv 文件编码，缺省为UTF-8。文件以BOM开始时以BOM为准(UTF-8/UTF-16LE/UTF-16BE)
+
+		Password          string // xlsx 打开加密文件的密码
+		UnzipSizeLimit    int64  // xlsx 解压后的总大小上限(字节)，0表示使用excelize的缺省值(16GB)
+		UnzipXMLSizeLimit int64  // xlsx 解压worksheet等xml时使用内存的上限(字节)，超出时使用临时文件，0表示使用excelize的缺省值(16MB)
+
+		Limits  Limits          // 读取workbook时的资源限制，超时时间从打开workbook开始计算
+		Context context.Context // 打开及读取workbook时检查的ctx，其deadline与 Limits.Timeout 中较早的一个生效
+	}
+
+	WorkbookOption func(p *WorkbookParams)
+
+	CsvQuoting byte
 )
 
 const (
@@ -23,6 +57,12 @@ const (
 	MatchLevelPerfect                   // 类型所有具有"eorm"标签的字段均已找到匹配的列
 )
 
+const (
+	CsvQuoteStrict CsvQuoting = iota // 按 RFC 4180 处理引号，引号不规范时报错
+	CsvQuoteLazy                     // 允许字段中出现不规范的引号
+	CsvQuoteNone                     // 不处理引号，引号作为普通字符
+)
+
 func NewParams(opts ...Option) *Params {
 	params := new(Params)
 	for _, opt := range opts {
@@ -40,6 +80,22 @@ func WithGenLastLayerNoMerged() Option   { return func(p *Params) { p.GenLastRow
 func WithTitleStartRow(r int) Option     { return func(p *Params) { p.TitleStartRow = max(r, 0) } }
 func WithMatchLevel(l MatchLevel) Option { return func(p *Params) { p.RequiredMatchLevel = l } }
 func WithParams(src *Params) Option      { return func(p *Params) { p.CopyFrom(src) } }
+func WithLimits(l Limits) Option         { return func(p *Params) { p.Limits = l } }
+func WithReflection() Option             { return func(p *Params) { p.UseReflection = true } }
+func WithTrackRows() Option              { return func(p *Params) { p.TrackRows = true } }
+func WithWorkbookOptions(opts ...WorkbookOption) Option {
+	return func(p *Params) { p.WorkbookOptions = append(p.WorkbookOptions, opts...) }
+}
+
+// WithSheetOptions DecodeWorkbook 读取 eorm_sheet 标签的值为sheet的属性时，在其他参数之后追加opts
+func WithSheetOptions(sheet string, opts ...Option) Option {
+	return func(p *Params) {
+		if p.SheetOptions == nil {
+			p.SheetOptions = make(map[string][]Option)
+		}
+		p.SheetOptions[sheet] = append(p.SheetOptions[sheet], opts...)
+	}
+}
 
 func (p *Params) MinRows(titleDepth int) int { return p.TitleStartRow + titleDepth }
 
@@ -52,6 +108,11 @@ func (p *Params) CopyFrom(src *Params) *Params {
 	p.GenLastRowNoMerged = src.GenLastRowNoMerged
 	p.TitleStartRow = src.TitleStartRow
 	p.RequiredMatchLevel = src.RequiredMatchLevel
+	p.Limits = src.Limits
+	p.UseReflection = src.UseReflection
+	p.TrackRows = src.TrackRows
+	p.WorkbookOptions = slices.Clone(src.WorkbookOptions)
+	p.SheetOptions = maps.Clone(src.SheetOptions)
 	return p
 }
 
@@ -64,3 +125,45 @@ func (l MatchLevel) Formalize() MatchLevel {
 	}
 	return MatchLevelPerfect
 }
+
+func NewWorkbookParams(opts ...WorkbookOption) *WorkbookParams {
+	params := new(WorkbookParams)
+	for _, opt := range opts {
+		opt(params)
+	}
+	return params
+}
+
+func (p *WorkbookParams) CopyFrom(src *WorkbookParams) *WorkbookParams {
+	*p = *src
+	return p
+}
+
+func WithWorkbookParams(src *WorkbookParams) WorkbookOption {
+	return func(p *WorkbookParams) { p.CopyFrom(src) }
+}
+
+func WithSheetName(name string) WorkbookOption   { return func(p *WorkbookParams) { p.SheetName = name } }
+func WithCsvComma(r rune) WorkbookOption         { return func(p *WorkbookParams) { p.Comma = r } }
+func WithCsvComment(r rune) WorkbookOption       { return func(p *WorkbookParams) { p.Comment = r } }
+func WithCsvQuoting(q CsvQuoting) WorkbookOption { return func(p *WorkbookParams) { p.Quoting = q } }
+func WithCsvEncoding(e encoding.Encoding) WorkbookOption {
+	return func(p *WorkbookParams) { p.Encoding = e }
+}
+func WithPassword(password string) WorkbookOption {
+	return func(p *WorkbookParams) { p.Password = password }
+}
+func WithUnzipSizeLimit(n int64) WorkbookOption {
+	return func(p *WorkbookParams) { p.UnzipSizeLimit = n }
+}
+func WithUnzipXMLSizeLimit(n int64) WorkbookOption {
+	return func(p *WorkbookParams) { p.UnzipXMLSizeLimit = n }
+}
+func WithWorkbookLimits(l Limits) WorkbookOption {
+	return func(p *WorkbookParams) { p.Limits = l }
+}
+func WithWorkbookContext(ctx context.Context) WorkbookOption {
+	return func(p *WorkbookParams) { p.Context = ctx }
+}
+
+func (p *WorkbookParams) startLimits() *limiter { return p.Limits.startContext(p.Context) }
@@ -1 +1,186 @@
 package eorm
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SheetSelector 从workbook中选择需要读取的sheet
+type SheetSelector func(wb Workbook) (Sheet, error)
+
+// FirstSheet 选择第一个sheet
+func FirstSheet() SheetSelector {
+	return SheetAt(0)
+}
+
+// SheetAt 选择下标为index的sheet
+func SheetAt(index int) SheetSelector {
+	return func(wb Workbook) (Sheet, error) {
+		return wb.GetSheet(index)
+	}
+}
+
+// SheetNamed 选择名称为name的sheet
+func SheetNamed(name string) SheetSelector {
+	return func(wb Workbook) (Sheet, error) {
+		return wb.GetSheetByName(name)
+	}
+}
+
+// SheetMatching 使用 FindSheet 选择与T最匹配的sheet
+func SheetMatching[T any](opts ...Option) SheetSelector {
+	return func(wb Workbook) (Sheet, error) {
+		return FindSheet[T](wb, opts...)
+	}
+}
+
+// sheetMatch sheet与类型的匹配程度
+type sheetMatch struct {
+	sheet   Sheet
+	perfect bool // 所有属性均找到了对应列
+	matched int  // 找到对应列的属性数
+	ordered int  // 列的顺序与属性的声明顺序一致的属性数
+}
+
+// FindSheet 使用 NewRowMapper 将T与wb中的每一个sheet进行匹配，返回最匹配的sheet，opts 中的 Params.TitleStartRow 等参数作用于所有sheet。
+// 匹配程度依次按：是否完全匹配、匹配的属性数、必需属性(required/not_null，没有时为所有匹配的属性)的列顺序与属性声明顺序一致的个数比较。
+// 表头不满足T的要求(如缺少必需的列)或者没有匹配任何属性的sheet被忽略，读取sheet出错时返回带有sheet名称的错误；
+// 没有sheet匹配时返回 ErrNotFound，
+// 最匹配的sheet不止一个时返回 ErrAmbiguousSheet
+func FindSheet[T any](wb Workbook, opts ...Option) (Sheet, error) {
+	if wb == nil {
+		return nil, ErrNil
+	}
+	typ := reflect.TypeFor[T]()
+	schema, err := CompileMapperSchema(typ)
+	if err != nil {
+		return nil, err
+	}
+	params := NewParams(opts...)
+	var best []*sheetMatch
+	for i := 0; i < wb.SheetCount(); i++ {
+		sheet, err := wb.GetSheet(i)
+		if err != nil {
+			return nil, fmt.Errorf("eorm: get sheet %d: %w", i, err)
+		}
+		mapper, _, err := BindRowMapper[T](schema, sheet, params)
+		if err != nil {
+			if errors.Is(err, ErrRequiredColumnNotFound) || errors.Is(err, ErrInsufficientMatchLevel) ||
+				errors.Is(err, ErrTitleMismatch) {
+				continue
+			}
+			return nil, fmt.Errorf("eorm: sheet %s: %w", sheet.GetName(), err)
+		}
+		if !mapper.IsMatched() {
+			continue
+		}
+		match := &sheetMatch{
+			sheet:   sheet,
+			perfect: mapper.IsPerfectMatch(),
+			matched: len(mapper.columns),
+			ordered: mapper.orderedFields(),
+		}
+		if len(best) == 0 {
+			best = append(best, match)
+			continue
+		}
+		switch c := match.compare(best[0]); {
+		case c > 0:
+			best = append(best[:0], match)
+		case c == 0:
+			best = append(best, match)
+		}
+	}
+	switch len(best) {
+	case 0:
+		return nil, fmt.Errorf("eorm: no sheet matches %s: %w", typ, ErrNotFound)
+	case 1:
+		return best[0].sheet, nil
+	default:
+		names := make([]string, len(best))
+		for i, m := range best {
+			names[i] = strconv.Quote(m.sheet.GetName())
+		}
+		return nil, fmt.Errorf("%w: %s match %s equally", ErrAmbiguousSheet, strings.Join(names, ", "), typ)
+	}
+}
+
+func (m *sheetMatch) compare(o *sheetMatch) int {
+	if m.perfect != o.perfect {
+		if m.perfect {
+			return 1
+		}
+		return -1
+	}
+	if c := cmp.Compare(m.matched, o.matched); c != 0 {
+		return c
+	}
+	return cmp.Compare(m.ordered, o.ordered)
+}
+
+// ReadAll 打开filePath，选择sheet(selector为nil时选择第一个sheet)并转换所有数据行，完成后关闭workbook。
+// 打开workbook的参数由 WithWorkbookOptions 提供，Params.Limits 同时作用于workbook的读取。
+// 任何一行转换失败时返回 RowError，遍历提前结束时返回对应的错误
+func ReadAll[T any](filePath string, selector SheetSelector, opts ...Option) ([]T, error) {
+	params := NewParams(opts...)
+	wb, err := NewWorkbook(filePath, params.workbookOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	return readAll[T](wb, selector, params)
+}
+
+// ReadAllByReadSeeker 与 ReadAll 相同，根据reader的内容识别格式，filename 的作用与 NewWorkbookByReadSeeker 相同
+func ReadAllByReadSeeker[T any](filename string, reader io.ReadSeeker, selector SheetSelector, opts ...Option) ([]T, error) {
+	params := NewParams(opts...)
+	wb, err := NewWorkbookByReadSeeker(filename, reader, params.workbookOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	return readAll[T](wb, selector, params)
+}
+
+// workbookOptions 未设置workbook的资源限制时，使用 Params.Limits
+func (p *Params) workbookOptions() []WorkbookOption {
+	if p.Limits.IsZero() {
+		return p.WorkbookOptions
+	}
+	return append([]WorkbookOption{WithWorkbookLimits(p.Limits)}, p.WorkbookOptions...)
+}
+
+func readAll[T any](wb Workbook, selector SheetSelector, params *Params) ([]T, error) {
+	if selector == nil {
+		selector = FirstSheet()
+	}
+	sheet, err := selector(wb)
+	if err != nil {
+		return nil, fmt.Errorf("eorm: select sheet: %w", err)
+	}
+	em, err := Open[T](sheet, WithParams(params))
+	if err != nil {
+		return nil, err
+	}
+	var results []T
+	for em.Next() {
+		obj, err := em.Current()
+		if err != nil {
+			return nil, RowError{RowIndex: em.rowIndex, Err: err}
+		}
+		results = append(results, *obj)
+	}
+	if err = em.stopError(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -1,8 +1,14 @@
 package eorm
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/xuri/excelize/v2"
@@ -139,3 +145,297 @@ func TestAllMergeCells(t *testing.T) {
 		fmt.Printf("合并区域: %s - %s, 起始值: %s\n", mc.GetStartAxis(), mc.GetEndAxis(), mc.GetCellValue())
 	}
 }
+
+// countingReader 不支持 io.ReaderAt，记录读取的字节数
+type countingReader struct {
+	io.ReadSeeker
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(p)
+	r.n += n
+	return n, err
+}
+
+func TestReadOnce(t *testing.T) {
+	// 已知是xlsx且没有密码时不识别格式，检查解压大小时读取的内容直接用于打开；xls读取合并单元格与打开共用读取的内容
+	tests := []struct {
+		file string
+		open func(io.ReadSeeker, ...WorkbookOption) (Workbook, error)
+		opts []WorkbookOption
+	}{
+		{"title.xlsx", NewXlsxWorkbookByReadSeeker, nil},
+		{"title.xlsx", NewXlsxWorkbookByReadSeeker, []WorkbookOption{WithWorkbookLimits(Limits{MaxUnzipSize: 1 << 20})}},
+		{"title.xls", NewXlsWorkbookByReadSeeker, nil},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &countingReader{ReadSeeker: bytes.NewReader(data)}
+		wb, err := tt.open(r, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = wb.Close()
+		if r.n != len(data) {
+			t.Fatalf("%s: expecting %d bytes read, got %d", tt.file, len(data), r.n)
+		}
+	}
+}
+
+func TestXlsxPassword(t *testing.T) {
+	f, err := excelize.OpenFile(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if _, err = f.WriteTo(buf, excelize.Options{Password: "password"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	encrypted := buf.Bytes()
+
+	if _, err = NewXlsxWorkbookByReadSeeker(bytes.NewReader(encrypted)); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("ErrPasswordRequired expected, got %v", err)
+	}
+	if _, err = NewXlsxWorkbookByReadSeeker(bytes.NewReader(encrypted), WithPassword("wrong")); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("ErrIncorrectPassword expected, got %v", err)
+	}
+	if _, err = OpenWorkbook(bytes.NewReader(encrypted)); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("ErrPasswordRequired expected, got %v", err)
+	}
+	// 提供了密码，但文件损坏或者没有加密时，不是密码错误
+	plain, err := os.ReadFile(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{
+		"truncated": plain[:len(plain)/2],
+		"garbage":   bytes.Repeat([]byte{0xff, 0x00}, 512),
+		"encrypted": encrypted[:len(encrypted)/2],
+	} {
+		_, err = NewXlsxWorkbookByReadSeeker(bytes.NewReader(data), WithPassword("password"))
+		if err == nil || errors.Is(err, ErrIncorrectPassword) {
+			t.Fatalf("%s: error other than ErrIncorrectPassword expected, got %v", name, err)
+		}
+	}
+	wb, err := NewXlsxWorkbookByReadSeeker(bytes.NewReader(plain), WithPassword("password"))
+	if err != nil {
+		t.Fatalf("unencrypted file with password: %v", err)
+	}
+	_ = wb.Close()
+	wb, err = NewWorkbookByReadSeeker("title.xlsx", bytes.NewReader(encrypted), WithPassword("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	sheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj1{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testTitle1(em, t)
+}
+
+func TestReadAll(t *testing.T) {
+	for _, file := range []string{"title.xlsx", "title.ods", "title.csv"} {
+		objs, err := ReadAll[TitleObj1](filepath.Join("testdata", file), nil)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		if len(objs) != len(title1Expectings) {
+			t.Fatalf("%s: expecting %d objects, got %d", file, len(title1Expectings), len(objs))
+		}
+		for i := range objs {
+			if !title1Expectings[i].Equals(&objs[i]) {
+				t.Fatalf("%s: expecting %+v, got %+v", file, title1Expectings[i], objs[i])
+			}
+		}
+	}
+
+	if _, err := ReadAll[TitleObj1](filepath.Join("testdata", "title.xlsx"), SheetNamed("not exist")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("ErrNotFound expected, got %v", err)
+	}
+	if _, err := ReadAll[TitleObj1](filepath.Join("testdata", "title.xlsx"), SheetAt(0),
+		WithLimits(Limits{MaxRows: 4})); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("ErrTooManyRows expected, got %v", err)
+	}
+	if _, err := ReadAll[int](filepath.Join("testdata", "title.xlsx"), FirstSheet()); err == nil {
+		t.Fatal("error expected for non-struct type")
+	}
+
+	// 打开workbook的参数
+	f, err := excelize.OpenFile(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if _, err = f.WriteTo(buf, excelize.Options{Password: "password"}); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	objs, err := ReadAllByReadSeeker[TitleObj1]("upload", bytes.NewReader(buf.Bytes()), nil,
+		WithWorkbookOptions(WithPassword("password")), WithMatchLevel(MatchLevelPerfect))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != len(title1Expectings) {
+		t.Fatalf("expecting %d objects, got %d", len(title1Expectings), len(objs))
+	}
+}
+
+func TestOpenTypeMismatch(t *testing.T) {
+	wb, err := NewWorkbook(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	sheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Open[string](sheet); err == nil {
+		t.Fatal("error expected for non-struct type")
+	}
+	if _, err = NewEORM[TitleObj1](sheet, reflect.TypeOf(TitleObj2{})); err == nil {
+		t.Fatal("error expected for mismatched type")
+	}
+	em, err := Open[TitleObj1](sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testTitle1(em, t)
+}
+
+// newSheetsWorkbook 按names的顺序创建sheet并写入对应的行，返回打开的xlsx workbook
+func newSheetsWorkbook(t *testing.T, names []string, sheets map[string][][]any) Workbook {
+	f := excelize.NewFile()
+	defer func() {
+		_ = f.Close()
+	}()
+	for i, name := range names {
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", name); err != nil {
+				t.Fatal(err)
+			}
+		} else if _, err := f.NewSheet(name); err != nil {
+			t.Fatal(err)
+		}
+		for r, row := range sheets[name] {
+			cell, _ := excelize.CoordinatesToCellName(1, r+1)
+			if err := f.SetSheetRow(name, cell, &row); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	buf := new(bytes.Buffer)
+	if err := f.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	wb, err := NewWorkbookByReadSeeker("sheets.xlsx", bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wb
+}
+
+type (
+	findObj struct {
+		ID    int64  `eorm:"编号,required"`
+		Name  string `eorm:"名称,required"`
+		Price string `eorm:"单价"`
+	}
+
+	// brokenSheet 读取任何行都出错
+	brokenSheet struct{ Sheet }
+
+	// brokenWorkbook 名称为broken的sheet替换为 brokenSheet
+	brokenWorkbook struct {
+		Workbook
+		broken string
+	}
+)
+
+var errBrokenRow = errors.New("broken row")
+
+func (s brokenSheet) GetRow(int) (Row, error) { return nil, errBrokenRow }
+
+func (wb brokenWorkbook) GetSheet(index int) (Sheet, error) {
+	sheet, err := wb.Workbook.GetSheet(index)
+	if err == nil && sheet.GetName() == wb.broken {
+		return brokenSheet{sheet}, nil
+	}
+	return sheet, err
+}
+
+func TestFindSheet(t *testing.T) {
+	sheets := map[string][][]any{
+		"说明":   {{"本文件包含以下数据"}},
+		"部分":   {{"编号", "名称", "备注"}, {1, "a", "x"}},
+		"缺少必需": {{"编号", "单价"}, {1, 2}},
+		"完整":   {{"单价", "编号", "名称"}, {2, 1, "a"}},
+		"倒序":   {{"名称", "编号", "单价"}, {"a", 1, 2}},
+		"复制":   {{"编号", "名称", "单价"}, {1, "a", 2}},
+		"空":    nil,
+		"重复":   {{"编号", "名称", "名称"}, {1, "a", "b"}},
+	}
+	tests := []struct {
+		names []string
+		want  string
+		err   error
+	}{
+		{names: []string{"说明", "部分", "缺少必需"}, want: "部分"},
+		{names: []string{"说明", "部分", "倒序"}, want: "倒序"},
+		// 完全匹配时，必需列的顺序与属性声明顺序一致的优先
+		{names: []string{"倒序", "完整", "说明"}, want: "完整"},
+		{names: []string{"完整", "复制"}, err: ErrAmbiguousSheet},
+		{names: []string{"说明", "缺少必需"}, err: ErrNotFound},
+		// 表头行数不足或者与title path不匹配的sheet被忽略
+		{names: []string{"空", "重复", "部分"}, want: "部分"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.names), func(t *testing.T) {
+			wb := newSheetsWorkbook(t, tt.names, sheets)
+			defer func() {
+				_ = wb.Close()
+			}()
+			sheet, err := FindSheet[findObj](wb)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("want %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sheet.GetName() != tt.want {
+				t.Fatalf("got sheet %s, want %s", sheet.GetName(), tt.want)
+			}
+			objs, err := readAll[findObj](wb, SheetMatching[findObj](), NewParams(WithIgnoreOutOfRange()))
+			if err != nil || len(objs) != 1 || objs[0].ID != 1 || objs[0].Name != "a" {
+				t.Fatalf("read matching sheet: %+v %v", objs, err)
+			}
+		})
+	}
+
+	// 读取sheet出错时不能忽略
+	wb := newSheetsWorkbook(t, []string{"说明", "部分"}, sheets)
+	defer func() {
+		_ = wb.Close()
+	}()
+	_, err := FindSheet[findObj](brokenWorkbook{Workbook: wb, broken: "说明"})
+	if !errors.Is(err, errBrokenRow) || !strings.Contains(err.Error(), "说明") {
+		t.Fatalf("want read error of sheet 说明, got %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package eorm
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// 属性的校验规则，由 Constraint 和映射类型决定，读取时由eorm检查
+const (
+	ValidatorColumnRequired = "column_required" // 表头中必须存在对应的列(required, not_null)
+	ValidatorNotEmpty       = "not_empty"       // 单元格不能为空(not_null)
+	ValidatorInt64          = "int64"           // 非空单元格必须能转换为int64
+	ValidatorFloat64        = "float64"         // 非空单元格必须能转换为float64
+	ValidatorBool           = "bool"            // 非空单元格必须为TRUE或FALSE(不区分大小写)
+)
+
+type (
+	// FieldSchema 一个需要映射的属性的描述。Type 为单元格值的类型(string/int64/float64/bool)，Slice 为true时映射多列。
+	// eorm 不支持自定义缺省值，单元格为空或者没有对应的列时属性为类型的零值，即 Default。
+	// Choices 和 Description 分别来自validate标签中的 oneof=a b c 和desc标签，与 GenerateTemplate 的下拉选项和批注相同
+	FieldSchema struct {
+		Name        string     `json:"name" yaml:"name"`
+		Path        TitlePath  `json:"path" yaml:"path"`
+		Encoded     string     `json:"encoded" yaml:"encoded"`
+		Type        string     `json:"type" yaml:"type"`
+		Slice       bool       `json:"slice" yaml:"slice"`
+		Constraint  Constraint `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+		Setter      bool       `json:"setter" yaml:"setter"`
+		Default     any        `json:"default" yaml:"default"`
+		Validators  []string   `json:"validators" yaml:"validators"`
+		Choices     []string   `json:"choices,omitempty" yaml:"choices,omitempty"`
+		Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	}
+
+	// TypeSchema 对象类型的可序列化描述，Fields 按属性声明的顺序排列
+	TypeSchema struct {
+		Type   string         `json:"type" yaml:"type"`
+		Depth  int            `json:"depth" yaml:"depth"`
+		Fields []*FieldSchema `json:"fields" yaml:"fields"`
+	}
+
+	// HeaderNode 表头树的节点，叶子节点的 Field 为对应的属性名。子节点按其包含的第一个属性的声明顺序排列
+	HeaderNode struct {
+		Title    string        `json:"title" yaml:"title"`
+		Field    string        `json:"field,omitempty" yaml:"field,omitempty"`
+		Children []*HeaderNode `json:"children,omitempty" yaml:"children,omitempty"`
+	}
+)
+
+// DescribeType 返回objType的 TypeSchema
+func DescribeType(objType reflect.Type) (*TypeSchema, error) {
+	schema, err := CompileMapperSchema(objType)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Describe(), nil
+}
+
+// Describe 返回可序列化的 TypeSchema
+func (s *MapperSchema) Describe() *TypeSchema {
+	ts := &TypeSchema{Type: s.typ.String(), Depth: s.tree.Depth()}
+	for _, fieldIndex := range slices.Sorted(maps.Keys(s.fields)) {
+		m := s.fields[fieldIndex]
+		fs := &FieldSchema{
+			Name:       m.fieldName,
+			Path:       m.titlePath.Clone(),
+			Encoded:    m.titlePath.Encode(),
+			Slice:      m.mappingType.IsSlice(),
+			Constraint: m.constraint,
+			Setter:     m.HasSetter,
+		}
+		fs.Description, fs.Choices = s.fieldNotes(m)
+		if m.constraint.NeedMapper() {
+			fs.Validators = append(fs.Validators, ValidatorColumnRequired)
+		}
+		if m.constraint.NeedValue() {
+			fs.Validators = append(fs.Validators, ValidatorNotEmpty)
+		}
+		switch m.mappingType {
+		case MTInt64, MTInt64Slice:
+			fs.Type, fs.Default = "int64", int64(0)
+			fs.Validators = append(fs.Validators, ValidatorInt64)
+		case MTFloat64, MTFloat64Slice:
+			fs.Type, fs.Default = "float64", float64(0)
+			fs.Validators = append(fs.Validators, ValidatorFloat64)
+		case MTBool, MTBoolSlice:
+			fs.Type, fs.Default = "bool", false
+			fs.Validators = append(fs.Validators, ValidatorBool)
+		default:
+			fs.Type, fs.Default = "string", ""
+		}
+		if fs.Slice {
+			fs.Default = []any{}
+		}
+		if fs.Validators == nil {
+			fs.Validators = []string{}
+		}
+		ts.Fields = append(ts.Fields, fs)
+	}
+	return ts
+}
+
+// HeaderTree 由 PathTree 生成表头树，根节点的 Title 为空
+func (s *MapperSchema) HeaderTree() *HeaderNode {
+	root, _ := s.headerNode("", s.tree.root)
+	return root
+}
+
+// headerNode 返回节点及其包含的最小属性下标
+func (s *MapperSchema) headerNode(title string, item TreeItem[int]) (*HeaderNode, int) {
+	node := &HeaderNode{Title: title}
+	if item == nil {
+		return node, -1
+	}
+	if item.IsValue() {
+		fieldIndex := item.GetValue()
+		if m := s.fields[fieldIndex]; m != nil {
+			node.Field = m.fieldName
+		}
+		return node, fieldIndex
+	}
+	first := -1
+	var firsts []int
+	for _, key := range item.ChildrenKeys() {
+		child, idx := s.headerNode(key, item.GetChild(key))
+		node.Children = append(node.Children, child)
+		firsts = append(firsts, idx)
+		if first < 0 || idx < first {
+			first = idx
+		}
+	}
+	order := make([]int, len(node.Children))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int { return firsts[a] - firsts[b] })
+	children := make([]*HeaderNode, len(order))
+	for i, o := range order {
+		children[i] = node.Children[o]
+	}
+	node.Children = children
+	return node, first
+}
+
+// JSONSchema 返回描述一行数据转换结果的JSON Schema(draft 2020-12)，属性名为字段名，
+// 扩展关键字 x-eorm-path 为title path，x-eorm-order 为属性的顺序。Choices 转换为 enum(slice属性为元素的enum)，
+// 单元格可以为空时 enum 中包括零值，Description 转换为 description
+func (ts *TypeSchema) JSONSchema() map[string]any {
+	properties := make(map[string]any, len(ts.Fields))
+	required := []string{}
+	for i, f := range ts.Fields {
+		prop := map[string]any{
+			"title":        f.Encoded,
+			"default":      f.Default,
+			"x-eorm-path":  f.Path,
+			"x-eorm-order": i,
+		}
+		if f.Description != "" {
+			prop["description"] = f.Description
+		}
+		typ := map[string]string{"string": "string", "int64": "integer", "float64": "number", "bool": "boolean"}[f.Type]
+		value := map[string]any{"type": typ}
+		if enum := f.enum(); enum != nil {
+			value["enum"] = enum
+		}
+		if f.Slice {
+			prop["type"] = "array"
+			prop["items"] = value
+		} else {
+			maps.Copy(prop, value)
+		}
+		if f.Constraint != ConstraintDefault {
+			prop["x-eorm-constraint"] = string(f.Constraint)
+		}
+		if f.Constraint.NeedValue() {
+			required = append(required, f.Name)
+			if f.Type == "string" && !f.Slice {
+				prop["minLength"] = 1
+			}
+		}
+		properties[f.Name] = prop
+	}
+	return map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                ts.Type,
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// enum 将 Choices 转换为属性类型的值，不能转换时返回nil
+func (f *FieldSchema) enum() []any {
+	if len(f.Choices) == 0 {
+		return nil
+	}
+	var enum []any
+	for _, choice := range f.Choices {
+		var v any
+		var err error
+		switch f.Type {
+		case "int64":
+			v, err = strconv.ParseInt(choice, 10, 64)
+		case "float64":
+			v, err = strconv.ParseFloat(choice, 64)
+		case "bool":
+			v, err = strconv.ParseBool(choice)
+		default:
+			v = choice
+		}
+		if err != nil {
+			return nil
+		}
+		enum = append(enum, v)
+	}
+	// 空单元格转换为零值
+	if !f.Constraint.NeedValue() {
+		zero := f.Default
+		if f.Slice {
+			zero = map[string]any{"int64": int64(0), "float64": float64(0), "bool": false}[f.Type]
+			if zero == nil {
+				zero = ""
+			}
+		}
+		if !slices.Contains(enum, zero) {
+			enum = append(enum, zero)
+		}
+	}
+	return enum
+}
@@ -0,0 +1,115 @@
+package eorm
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type schemaObj struct {
+	Id    int64    `eorm:"序号,required" validate:"oneof=1 2 3"`
+	Name  string   `eorm:"名称,not_null" validate:"required,oneof=a 'b c'" desc:"名称说明"`
+	Score float64  `eorm:"分数" validate:"oneof=x y"`
+	Tags  []string `eorm:"标签" validate:"oneof=t1 t2"`
+	Skip  string
+}
+
+func TestDescribeType(t *testing.T) {
+	ts, err := DescribeType(reflect.TypeOf(schemaObj{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.Depth != 1 || len(ts.Fields) != 4 {
+		t.Fatalf("unexpected schema: %+v", ts)
+	}
+	expectings := []struct {
+		name       string
+		typ        string
+		slice      bool
+		validators []string
+	}{
+		{"Id", "int64", false, []string{ValidatorColumnRequired, ValidatorInt64}},
+		{"Name", "string", false, []string{ValidatorColumnRequired, ValidatorNotEmpty}},
+		{"Score", "float64", false, []string{ValidatorFloat64}},
+		{"Tags", "string", true, []string{}},
+	}
+	for i, e := range expectings {
+		f := ts.Fields[i]
+		if f.Name != e.name || f.Type != e.typ || f.Slice != e.slice || !slices.Equal(f.Validators, e.validators) {
+			t.Fatalf("field %d: unexpected %+v", i, f)
+		}
+	}
+	if !slices.Equal(ts.Fields[1].Choices, []string{"a", "b c"}) || ts.Fields[1].Description != "名称说明" ||
+		ts.Fields[0].Description != "" {
+		t.Fatalf("unexpected choices or description: %+v %+v", ts.Fields[0], ts.Fields[1])
+	}
+
+	data, err := json.Marshal(ts.JSONSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var js struct {
+		Type       string                    `json:"type"`
+		Required   []string                  `json:"required"`
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	if err = json.Unmarshal(data, &js); err != nil {
+		t.Fatal(err)
+	}
+	if js.Type != "object" || !slices.Equal(js.Required, []string{"Name"}) || len(js.Properties) != 4 {
+		t.Fatalf("unexpected json schema: %s", data)
+	}
+	if js.Properties["Id"]["type"] != "integer" || js.Properties["Tags"]["type"] != "array" ||
+		js.Properties["Score"]["title"] != "分数" {
+		t.Fatalf("unexpected json schema: %s", data)
+	}
+	// 可以为空的属性包括零值，不能转换为属性类型的选项被忽略
+	enums := map[string]any{
+		"Id":    []any{1.0, 2.0, 3.0, 0.0},
+		"Name":  []any{"a", "b c"},
+		"Score": nil,
+		"Tags":  nil,
+	}
+	for name, enum := range enums {
+		if got := js.Properties[name]["enum"]; !reflect.DeepEqual(got, enum) {
+			t.Fatalf("%s: expecting enum %v, got %v", name, enum, got)
+		}
+	}
+	items := js.Properties["Tags"]["items"].(map[string]any)
+	if !reflect.DeepEqual(items["enum"], []any{"t1", "t2", ""}) {
+		t.Fatalf("unexpected items: %v", items)
+	}
+	if js.Properties["Name"]["description"] != "名称说明" || js.Properties["Id"]["description"] != nil {
+		t.Fatalf("unexpected description: %s", data)
+	}
+}
+
+func TestHeaderTree(t *testing.T) {
+	schema, err := CompileMapperSchema(reflect.TypeOf(TitleObj1{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := schema.HeaderTree()
+	var titles []string
+	for _, child := range root.Children {
+		titles = append(titles, child.Title)
+	}
+	if !slices.Equal(titles, []string{"序号", "名称", "第一级"}) {
+		t.Fatalf("unexpected top level: %v", titles)
+	}
+	titles = titles[:0]
+	for _, child := range root.Children[2].Children {
+		titles = append(titles, child.Title)
+	}
+	if !slices.Equal(titles, []string{"第二级", "反引号`测试", "双引号\"测试"}) {
+		t.Fatalf("unexpected second level: %v", titles)
+	}
+	leaf := root.Children[0].Children[0].Children[0]
+	if leaf.Title != "" || leaf.Field != "Id" || len(leaf.Children) != 0 {
+		t.Fatalf("unexpected leaf: %+v", leaf)
+	}
+	if _, err = json.Marshal(root); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,343 @@
+package eorm
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/width"
+)
+
+const (
+	TemplateListSheet = "_eorm_lists" // 下拉选项超过Excel的长度限制时，选项写入该隐藏sheet
+
+	templateMinWidth = 8
+	templateMaxWidth = 50
+)
+
+type (
+	// TemplateParams 生成模板时使用的参数
+	TemplateParams struct {
+		SheetName     string // sheet名称，缺省为"Sheet1"
+		TitleStartRow int    // 表头的起始行(行号从0开始)，所有小于0的值均被认为是0
+		DataRows      int    // 数据校验(下拉选项)覆盖的数据行数，缺省为1000
+	}
+
+	TemplateOption func(p *TemplateParams)
+
+	// templateColumn 模板中的一列，对应一个属性或者slice属性的一个元素(element)
+	templateColumn struct {
+		field   int
+		element int
+		path    TitlePath
+		desc    string
+		choices []string
+	}
+
+	// headerCell 表头中的一个(合并)单元格，area 的行下标相对于表头的第一行
+	headerCell struct {
+		title string
+		area  CellRange
+	}
+)
+
+func NewTemplateParams(opts ...TemplateOption) *TemplateParams {
+	p := &TemplateParams{SheetName: "Sheet1", DataRows: 1000}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func WithTemplateSheetName(name string) TemplateOption {
+	return func(p *TemplateParams) { p.SheetName = name }
+}
+func WithTemplateStartRow(r int) TemplateOption {
+	return func(p *TemplateParams) { p.TitleStartRow = max(r, 0) }
+}
+func WithTemplateDataRows(n int) TemplateOption {
+	return func(p *TemplateParams) { p.DataRows = n }
+}
+
+// GenerateTemplate 根据T的eorm标签生成只有表头的xlsx模板并写入w：
+//   - 多级表头按title path合并单元格，空的下级标题与上级标题纵向合并
+//   - 表头加粗并冻结
+//   - validate标签中的 oneof=a b c 生成数据行的下拉选项
+//   - desc标签作为表头最后一级标题的批注
+//   - 列宽由标题及下拉选项的显示宽度决定
+func GenerateTemplate[T any](w io.Writer, opts ...TemplateOption) error {
+	schema, err := CompileMapperSchema(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+	if len(schema.fields) == 0 {
+		return fmt.Errorf("eorm: no eorm tag found in %s", schema.typ)
+	}
+	params := NewTemplateParams(opts...)
+	if params.SheetName == "" {
+		params.SheetName = "Sheet1"
+	}
+	f, err := newTemplateFile(schema, params)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return f.Write(w)
+}
+
+// templateColumns 按表头树的顺序返回每一列
+func (s *MapperSchema) templateColumns() []*templateColumn {
+	byName := make(map[string]*ColumnMapper, len(s.fields))
+	for _, m := range s.fields {
+		byName[m.fieldName] = m
+	}
+	var columns []*templateColumn
+	var walk func(node *HeaderNode, path TitlePath)
+	walk = func(node *HeaderNode, path TitlePath) {
+		if len(node.Children) == 0 {
+			m := byName[node.Field]
+			if m == nil {
+				return
+			}
+			desc, choices := s.fieldNotes(m)
+			columns = append(columns, &templateColumn{
+				field:   m.fieldIndex,
+				path:    path,
+				desc:    desc,
+				choices: choices,
+			})
+			return
+		}
+		for _, child := range node.Children {
+			walk(child, append(path.Clone(), child.Title))
+		}
+	}
+	walk(s.HeaderTree(), nil)
+	return columns
+}
+
+// fieldNotes 返回属性desc标签的说明和validate标签中 oneof 的选项，模板和 FieldSchema 使用同样的来源
+func (s *MapperSchema) fieldNotes(m *ColumnMapper) (string, []string) {
+	tag := s.typ.Field(m.fieldIndex).Tag
+	return tag.Get("desc"), parseOneOf(tag.Get("validate"))
+}
+
+// parseOneOf 解析validate标签中的 oneof=a b c，选项以空格分隔，包含空格的选项使用单引号
+func parseOneOf(tag string) []string {
+	for _, rule := range strings.Split(tag, ",") {
+		values, ok := strings.CutPrefix(strings.TrimSpace(rule), "oneof=")
+		if !ok {
+			continue
+		}
+		var choices []string
+		for values = strings.TrimSpace(values); values != ""; values = strings.TrimSpace(values) {
+			var choice string
+			if strings.HasPrefix(values, "'") {
+				end := strings.Index(values[1:], "'")
+				if end < 0 {
+					choice, values = values[1:], ""
+				} else {
+					choice, values = values[1:end+1], values[end+2:]
+				}
+			} else {
+				choice, values, _ = strings.Cut(values, " ")
+			}
+			choices = append(choices, choice)
+		}
+		return choices
+	}
+	return nil
+}
+
+func newTemplateFile(schema *MapperSchema, params *TemplateParams) (*excelize.File, error) {
+	columns := schema.templateColumns()
+	depth := schema.tree.Depth()
+	startRow := params.TitleStartRow
+	sheet := params.SheetName
+
+	f := excelize.NewFile()
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("excel/xlsx: set sheet name: %w", err)
+	}
+	if err := writeTemplate(f, sheet, columns, depth, startRow, params.DataRows); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func writeTemplate(f *excelize.File, sheet string, columns []*templateColumn, depth, startRow, dataRows int) error {
+	cellName := func(col, row int) string { return ColumnLetter(col) + fmt.Sprint(row+1) }
+	style, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"F2F2F2"}},
+		Border: []excelize.Border{
+			{Type: "left", Color: "BFBFBF", Style: 1},
+			{Type: "top", Color: "BFBFBF", Style: 1},
+			{Type: "right", Color: "BFBFBF", Style: 1},
+			{Type: "bottom", Color: "BFBFBF", Style: 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("excel/xlsx: new style: %w", err)
+	}
+
+	widths := make([]int, len(columns))
+	for _, hc := range headerLayout(columns, depth) {
+		area := hc.area
+		if err = f.SetCellStr(sheet, cellName(area.FirstColumn, startRow+area.FirstRow), hc.title); err != nil {
+			return fmt.Errorf("excel/xlsx: set cell: %w", err)
+		}
+		if area.LastColumn > area.FirstColumn || area.LastRow > area.FirstRow {
+			if err = f.MergeCell(sheet, cellName(area.FirstColumn, startRow+area.FirstRow),
+				cellName(area.LastColumn, startRow+area.LastRow)); err != nil {
+				return fmt.Errorf("excel/xlsx: merge cell: %w", err)
+			}
+		}
+		// 合并单元格的标题宽度平均分配到每一列
+		span := area.LastColumn - area.FirstColumn + 1
+		for k := area.FirstColumn; k <= area.LastColumn; k++ {
+			widths[k] = max(widths[k], (displayWidth(hc.title)+span-1)/span)
+		}
+	}
+	if err = f.SetCellStyle(sheet, cellName(0, startRow), cellName(len(columns)-1, startRow+depth-1), style); err != nil {
+		return fmt.Errorf("excel/xlsx: set style: %w", err)
+	}
+
+	listColumn := 0
+	for i, c := range columns {
+		for _, choice := range c.choices {
+			widths[i] = max(widths[i], displayWidth(choice))
+		}
+		width := float64(min(max(widths[i]+2, templateMinWidth), templateMaxWidth))
+		if err = f.SetColWidth(sheet, ColumnLetter(i), ColumnLetter(i), width); err != nil {
+			return fmt.Errorf("excel/xlsx: set column width: %w", err)
+		}
+		if c.desc != "" {
+			if err = f.AddComment(sheet, excelize.Comment{
+				Cell: cellName(i, startRow+lastTitleLevel(c.path)),
+				Text: c.desc,
+			}); err != nil {
+				return fmt.Errorf("excel/xlsx: add comment: %w", err)
+			}
+		}
+		if len(c.choices) > 0 && dataRows > 0 {
+			if err = addChoices(f, sheet, cellName(i, startRow+depth)+":"+cellName(i, startRow+depth+dataRows-1),
+				c.choices, &listColumn); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      startRow + depth,
+		TopLeftCell: cellName(0, startRow+depth),
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return fmt.Errorf("excel/xlsx: set panes: %w", err)
+	}
+	return nil
+}
+
+// addChoices 为sqref添加下拉选项，超过长度限制时将选项写入隐藏的 TemplateListSheet 并引用
+func addChoices(f *excelize.File, sheet, sqref string, choices []string, listColumn *int) error {
+	dv := excelize.NewDataValidation(true)
+	dv.Sqref = sqref
+	if err := dv.SetDropList(choices); err != nil {
+		if *listColumn == 0 {
+			if _, err = f.NewSheet(TemplateListSheet); err != nil {
+				return fmt.Errorf("excel/xlsx: new sheet: %w", err)
+			}
+			if err = f.SetSheetVisible(TemplateListSheet, false); err != nil {
+				return fmt.Errorf("excel/xlsx: hide sheet: %w", err)
+			}
+		}
+		col := ColumnLetter(*listColumn)
+		*listColumn++
+		for k, choice := range choices {
+			if err = f.SetCellStr(TemplateListSheet, fmt.Sprintf("%s%d", col, k+1), choice); err != nil {
+				return fmt.Errorf("excel/xlsx: set cell: %w", err)
+			}
+		}
+		dv.SetSqrefDropList(fmt.Sprintf("'%s'!$%s$1:$%s$%d", TemplateListSheet, col, col, len(choices)))
+	}
+	if err := f.AddDataValidation(sheet, dv); err != nil {
+		return fmt.Errorf("excel/xlsx: add data validation: %w", err)
+	}
+	return nil
+}
+
+// headerLayout 计算多级表头中每个标题的位置：同一上级标题下标题相同的相邻列横向合并(slice属性的各个元素除外)，
+// 所有列的下级标题均为空时纵向合并
+func headerLayout(columns []*templateColumn, depth int) []headerCell {
+	var cells []headerCell
+	for level := 0; level < depth; level++ {
+		for first := 0; first < len(columns); {
+			title := columns[first].path[level]
+			last := first
+			for last+1 < len(columns) && title != "" &&
+				slices.Equal(columns[last+1].path[:level+1], columns[first].path[:level+1]) &&
+				!(columns[last+1].element > 0 && level >= lastTitleLevel(columns[last+1].path)) {
+				last++
+			}
+			if title == "" {
+				first = last + 1
+				continue
+			}
+			bottom := level
+			for bottom+1 < depth && allEmptyAt(columns[first:last+1], bottom+1) {
+				bottom++
+			}
+			cells = append(cells, headerCell{
+				title: title,
+				area:  CellRange{FirstRow: level, FirstColumn: first, LastRow: bottom, LastColumn: last},
+			})
+			first = last + 1
+		}
+	}
+	return cells
+}
+
+// lastTitleLevel 最后一个非空标题的层级，批注添加在该单元格上
+func lastTitleLevel(path TitlePath) int {
+	for i := len(path) - 1; i > 0; i-- {
+		if path[i] != "" {
+			return i
+		}
+	}
+	return 0
+}
+
+func allEmptyAt(columns []*templateColumn, level int) bool {
+	for _, c := range columns {
+		if c.path[level] != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// displayWidth 字符串的显示宽度，全角字符计为2
+func displayWidth(s string) int {
+	n := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,112 @@
+package eorm
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type templateObj struct {
+	ID     int64  `eorm:"序号//" desc:"从1开始的序号"`
+	Name   string `eorm:"名称//"`
+	Email  string `eorm:"联系方式/邮箱/地址"`
+	Phone  string `eorm:"联系方式/电话/手机"`
+	Fixed  string `eorm:"联系方式/电话/座机"`
+	Level  string `eorm:"等级//" validate:"required,oneof=A B 'C D'"`
+	Active bool   `eorm:"状态//"`
+}
+
+func TestGenerateTemplate(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenerateTemplate[templateObj](buf, WithTemplateSheetName("用户"), WithTemplateStartRow(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	wb, err := NewWorkbookByReadSeeker("template.xlsx", bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	sheet, err := wb.GetSheetByName("用户")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := NewEORM[templateObj](sheet, reflect.TypeFor[templateObj](), WithTitleStartRow(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.IsPerfectMatch() {
+		t.Fatal("template should match the type perfectly")
+	}
+	merged, err := GetMergedRanges(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ranges []string
+	for _, m := range merged {
+		ranges = append(ranges, m.String())
+	}
+	want := map[string]bool{"A2:A4": true, "B2:B4": true, "C2:E2": true, "C3:C3": false, "D3:E3": true, "F2:F4": true, "G2:G4": true}
+	got := make(map[string]bool)
+	for _, r := range ranges {
+		got[r] = true
+	}
+	for r, ok := range want {
+		if got[r] != ok {
+			t.Fatalf("merged ranges %v, %s expected %t", ranges, r, ok)
+		}
+	}
+	if len(ranges) != 6 {
+		t.Fatalf("merged ranges %v", ranges)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	dvs, err := f.GetDataValidations("用户")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dvs) != 1 || dvs[0].Sqref != "F5:F1004" || dvs[0].Formula1 != `"A,B,C D"` {
+		t.Fatalf("data validations %+v", dvs)
+	}
+	comments, err := f.GetComments("用户")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].Cell != "A2" || comments[0].Text != "从1开始的序号" {
+		t.Fatalf("comments %+v", comments)
+	}
+	panes, err := f.GetPanes("用户")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !panes.Freeze || panes.YSplit != 4 {
+		t.Fatalf("panes %+v", panes)
+	}
+}
+
+func TestParseOneOf(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"", nil},
+		{"required", nil},
+		{"oneof=a b c", []string{"a", "b", "c"}},
+		{"required, oneof='x y' z ", []string{"x y", "z"}},
+	}
+	for _, test := range tests {
+		if got := parseOneOf(test.tag); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseOneOf(%q) = %q, want %q", test.tag, got, test.want)
+		}
+	}
+}
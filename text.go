@@ -0,0 +1,141 @@
+package eorm
+
+import (
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+type (
+	// textSheet 在打开时已将全部单元格转换为文本的sheet，用于csv、ods等格式
+	textSheet struct {
+		format  string // 用于错误信息，如: csv, ods
+		name    string
+		allRows [][]string
+		merged  []CellRange // ods中 number-columns-spanned/number-rows-spanned 的单元格
+	}
+
+	textRowIterator struct {
+		curRow int
+		sheet  *textSheet
+	}
+
+	textRow struct {
+		format string
+		cols   []string
+	}
+)
+
+func (t *textSheet) GetName() string {
+	return t.name
+}
+
+// MergedRanges 返回ods的合并单元格，csv没有合并单元格
+func (t *textSheet) MergedRanges() ([]CellRange, error) {
+	return t.merged, nil
+}
+
+func (t *textSheet) RowCount() int {
+	return len(t.allRows)
+}
+
+func (t *textSheet) GetRow(index int) (Row, error) {
+	if index < 0 || index >= len(t.allRows) {
+		return nil, ErrOutOfRange
+	}
+	return textRow{format: t.format, cols: t.allRows[index]}, nil
+}
+
+func (r textRow) ColumnCount() int {
+	return len(r.cols)
+}
+
+func (r textRow) GetColumn(index int) (string, error) {
+	if index < 0 || index >= len(r.cols) {
+		return "", ErrOutOfRange
+	}
+	return r.cols[index], nil
+}
+
+func (r textRow) GetInt64Column(index int) (int64, error) {
+	v, err := r.GetColumn(index)
+	if err != nil {
+		return 0, err
+	}
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, ErrEmptyCell
+	}
+
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("excel/%s: string to int64 %w: %w", r.format, ErrParseError, err)
+	}
+	return i, nil
+}
+
+func (r textRow) GetFloat64Column(index int) (float64, error) {
+	v, err := r.GetColumn(index)
+	if err != nil {
+		return 0, err
+	}
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, ErrEmptyCell
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("excel/%s: string to float64 %w: %w", r.format, ErrParseError, err)
+	}
+	return f, nil
+}
+
+func (r textRow) GetBoolColumn(index int) (bool, error) {
+	v, err := r.GetColumn(index)
+	if err != nil {
+		return false, err
+	}
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return false, ErrEmptyCell
+	}
+
+	v = strings.ToUpper(v)
+	switch v {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	default:
+		return false, fmt.Errorf("excel/%s: string to bool %w: unknown value: %s", r.format, ErrParseError, v)
+	}
+}
+
+func (r textRow) AllColumns() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i, s := range r.cols {
+			if !yield(i, s) {
+				return
+			}
+		}
+	}
+}
+
+func (it *textRowIterator) Next() bool {
+	it.curRow++
+	return it.curRow < len(it.sheet.allRows)
+}
+
+func (it *textRowIterator) Current() (Row, error) {
+	if it.curRow < 0 {
+		return nil, ErrExcelNotInitialized
+	}
+	if it.curRow >= len(it.sheet.allRows) {
+		return nil, ErrEof
+	}
+	return it.sheet.GetRow(it.curRow)
+}
+
+func (it *textRowIterator) Close() error { return nil }
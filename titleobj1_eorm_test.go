@@ -0,0 +1,175 @@
+// Code generated by "eormgen --type TitleObj1,TitleObj2,parallelObj --tests --no-register"; DO NOT EDIT.
+
+package eorm
+
+// titleObj1Decoder 由 eormgen 生成的 TitleObj1 的 RowDecoder
+type titleObj1Decoder struct{}
+
+func (titleObj1Decoder) DecodeRow(row Row, columns [][]int, params *Params) (*TitleObj1, error) {
+	obj := new(TitleObj1)
+	// Id: 序号//
+	if cols := columns[0]; len(cols) > 0 {
+		v, err := ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Id = v
+	}
+	// Name: 名称//
+	if cols := columns[1]; len(cols) > 0 {
+		v, err := ColumnString(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Name = v
+	}
+	// Numbers: 第一级/第二级/第三级
+	if cols := columns[2]; len(cols) > 0 {
+		vs := make([]Integer, len(cols))
+		for i, col := range cols {
+			v, err := ColumnInt64(row, col, "", params)
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = Integer(v)
+		}
+		obj.Numbers = vs
+	}
+	// Bool: 第一级/反引号%60测试/空%20格
+	if cols := columns[3]; len(cols) > 0 {
+		v, err := ColumnBool(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Bool = v
+	}
+	// Slash: 第一级/双引号%22测试/反斜杠%5C
+	if cols := columns[4]; len(cols) > 0 {
+		v, err := ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.SetSlash(v)
+	}
+	// Num: 第一级/双引号%22测试/第三级
+	if cols := columns[5]; len(cols) > 0 {
+		v, err := ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Num = Integer(v)
+	}
+	return obj, nil
+}
+
+func (titleObj1Decoder) Fingerprint() string {
+	return "deb3da3b5b625dd2"
+}
+
+// titleObj2Decoder 由 eormgen 生成的 TitleObj2 的 RowDecoder
+type titleObj2Decoder struct{}
+
+func (titleObj2Decoder) DecodeRow(row Row, columns [][]int, params *Params) (*TitleObj2, error) {
+	obj := new(TitleObj2)
+	// Id: 序号//
+	if cols := columns[0]; len(cols) > 0 {
+		v, err := ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Id = v
+	}
+	// Name: 名称//
+	if cols := columns[1]; len(cols) > 0 {
+		v, err := ColumnString(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Name = v
+	}
+	// Numbers: 第一级/第二级/第三级
+	if cols := columns[2]; len(cols) > 0 {
+		vs := make([]int64, len(cols))
+		for i, col := range cols {
+			v, err := ColumnInt64(row, col, "", params)
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = v
+		}
+		obj.SetNumbers(vs)
+	}
+	// Bool: 第一级/反引号%60测试/空%20格
+	if cols := columns[3]; len(cols) > 0 {
+		v, err := ColumnString(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Bool = v
+	}
+	// Slash: 第一级/双引号%22测试/反斜杠%5C
+	if cols := columns[4]; len(cols) > 0 {
+		v, err := ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.SetSlash(v)
+	}
+	// Num: 第一级/双引号%22测试/第三级
+	if cols := columns[5]; len(cols) > 0 {
+		v, err := ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Num = Integer(v)
+	}
+	// NoTag1: 第一级/不存在/一列
+	if cols := columns[6]; len(cols) > 0 {
+		v, err := ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.NoTag1 = v
+	}
+	return obj, nil
+}
+
+func (titleObj2Decoder) Fingerprint() string {
+	return "2f634ba9c70d8847"
+}
+
+// parallelObjDecoder 由 eormgen 生成的 parallelObj 的 RowDecoder
+type parallelObjDecoder struct{}
+
+func (parallelObjDecoder) DecodeRow(row Row, columns [][]int, params *Params) (*parallelObj, error) {
+	obj := new(parallelObj)
+	// Id: 序号
+	if cols := columns[0]; len(cols) > 0 {
+		v, err := ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Id = v
+	}
+	// Name: 名称
+	if cols := columns[1]; len(cols) > 0 {
+		v, err := ColumnString(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Name = v
+	}
+	// Score: 分数
+	if cols := columns[2]; len(cols) > 0 {
+		v, err := ColumnInt64(row, cols[0], "", params)
+		if err != nil {
+			return nil, err
+		}
+		obj.Score = v
+	}
+	return obj, nil
+}
+
+func (parallelObjDecoder) Fingerprint() string {
+	return "4a70f58394a3fae3"
+}
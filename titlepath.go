@@ -433,7 +433,7 @@ func (m *TitleLayer[T]) Values() (map[int]T, error) {
 			continue
 		}
 		if !item.IsValue() {
-			return nil, fmt.Errorf("eorm: not a value at column %d", idx)
+			return nil, fmt.Errorf("%w: not a value at column %d", ErrTitleMismatch, idx)
 		}
 		ret = ret.Put(idx, item.GetValue())
 	}
@@ -449,7 +449,7 @@ func MatchTitlePath[T any](tree *PathTree[T], sheet Sheet, params *Params) (map[
 	startRow := params.TitleStartRow
 	rowCount := sheet.RowCount()
 	if rowCount < depth+startRow {
-		return nil, errors.New("eorm: row not enough")
+		return nil, fmt.Errorf("%w: row not enough", ErrTitleMismatch)
 	}
 	layer := NewTitleLayer(tree.root)
 	for i := startRow; i < depth+startRow; i++ {
@@ -458,7 +458,7 @@ func MatchTitlePath[T any](tree *PathTree[T], sheet Sheet, params *Params) (map[
 			return nil, fmt.Errorf("eorm: get row %d: %w", i, err)
 		}
 		if row == nil {
-			return nil, fmt.Errorf("eorm: get row %d nil", i)
+			return nil, fmt.Errorf("%w: get row %d nil", ErrTitleMismatch, i)
 		}
 		layer, err = layer.NextRow(row)
 		if err != nil {
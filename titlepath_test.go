@@ -1,8 +1,11 @@
 package eorm
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/stephenfire/go-tools"
@@ -82,3 +85,120 @@ func TestTitleEscape(t *testing.T) {
 	tp := TitlePath(ss)
 	t.Logf("%s", tp.Encode())
 }
+
+func TestBuildTitleColumns(t *testing.T) {
+	wb, err := NewWorkbook(filepath.Join("testdata", "title.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	sheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		c = SourceCell
+		m = SourceMerged
+		w = SourceWildcard
+	)
+	tests := []struct {
+		opts    []Option
+		letters []string
+		encoded []string
+		sources [][]SegmentSource
+	}{
+		{
+			letters: []string{"A", "B", "D", "H"},
+			encoded: []string{"序号//", "名称//", "第一级/反引号%60测试/斜杠%2F", "第一级/第二级/第三级"},
+			sources: [][]SegmentSource{{c, c, c}, {c, m, m}, {m, m, c}, {m, c, c}},
+		},
+		{
+			opts:    []Option{WithFirstRowWildcard()},
+			letters: []string{"A", "B", "D", "H"},
+			encoded: []string{"//", "//", "/反引号%60测试/斜杠%2F", "/第二级/第三级"},
+			sources: [][]SegmentSource{{w, c, c}, {w, m, m}, {w, m, c}, {w, c, c}},
+		},
+	}
+	indexes := []int{0, 1, 3, 7}
+	for _, test := range tests {
+		tcs, err := BuildTitleColumns(sheet, 3, test.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tps, err := BuildTitlePaths(sheet, 3, test.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tcs.Paths(), tps) {
+			t.Fatalf("paths of columns %v not match title paths %v", tcs.Paths(), tps)
+		}
+		for i, idx := range indexes {
+			tc := tcs[idx]
+			if tc.Index != idx || tc.Letter != test.letters[i] || tc.Encoded != test.encoded[i] ||
+				!reflect.DeepEqual(tc.Sources, test.sources[i]) {
+				t.Fatalf("column %d: unexpected %+v", idx, tc)
+			}
+		}
+	}
+
+	for idx, letter := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		if got := ColumnLetter(idx); got != letter {
+			t.Fatalf("ColumnLetter(%d): expecting %s, got %s", idx, letter, got)
+		}
+	}
+}
+
+func TestSuggestTitleLayout(t *testing.T) {
+	titleMerged := []string{"A1:A3", "B1:B3", "C1:H1", "C2:D2", "E2:F2"}
+	tests := []struct {
+		file        string
+		merged      []string // nil 时不检查
+		unsupported bool     // 不支持读取合并单元格
+		startRow    int
+		depth       int
+	}{
+		{"title.xlsx", titleMerged, false, 0, 3},
+		{"title.ods", titleMerged, false, 0, 3},
+		{"title.csv", []string{}, false, 0, 3},
+		{"title.xls", append([]string{"G2:G3"}, titleMerged...), false, 0, 4},
+		{"title_start_at_2.xlsx", nil, false, 2, 3},
+	}
+	for _, test := range tests {
+		wb, err := NewWorkbook(filepath.Join("testdata", test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheet, err := wb.GetSheet(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ranges, err := GetMergedRanges(sheet)
+		switch {
+		case test.unsupported:
+			if !errors.Is(err, errors.ErrUnsupported) {
+				t.Fatalf("%s: expecting ErrUnsupported, got %v", test.file, err)
+			}
+		case test.merged != nil:
+			if err != nil {
+				t.Fatalf("%s: %v", test.file, err)
+			}
+			var names []string
+			for _, r := range ranges {
+				names = append(names, r.String())
+			}
+			slices.Sort(names)
+			expecting := slices.Sorted(slices.Values(test.merged))
+			if !slices.Equal(names, expecting) {
+				t.Fatalf("%s: expecting merged %v, got %v", test.file, expecting, names)
+			}
+		}
+		startRow, depth := SuggestTitleLayout(sheet, 10)
+		if startRow != test.startRow || depth != test.depth {
+			t.Fatalf("%s: expecting start row %d depth %d, got %d %d", test.file, test.startRow, test.depth, startRow, depth)
+		}
+		_ = wb.Close()
+	}
+}
@@ -3,67 +3,151 @@ package eorm
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
-type TitlePaths []TitlePath
+type (
+	TitlePaths []TitlePath
+
+	// SegmentSource title path中一级标题的来源
+	SegmentSource string
+
+	// TitleColumn 表头中一列的title path及其每一级标题的来源，Sources[i] 对应 Path[i]
+	TitleColumn struct {
+		Index   int             `json:"index" yaml:"index"`
+		Letter  string          `json:"letter" yaml:"letter"`
+		Path    TitlePath       `json:"path" yaml:"path"`
+		Encoded string          `json:"encoded" yaml:"encoded"`
+		Sources []SegmentSource `json:"sources" yaml:"sources"`
+	}
+
+	TitleColumns []TitleColumn
+)
+
+const (
+	SourceCell     SegmentSource = "cell"     // 单元格的值(包括未被认为是合并单元格的空值)
+	SourceMerged   SegmentSource = "merged"   // 空单元格被认为是合并单元格，使用左侧单元格的值
+	SourceWildcard SegmentSource = "wildcard" // GenWildcardForFirstRow 时第一行生成的通配符
+)
 
 func BuildTitlePaths(sheet Sheet, depth int, opts ...Option) (TitlePaths, error) {
+	columns, _, err := buildTitleColumns(sheet, depth, opts...)
+	return columns, err
+}
+
+// BuildTitleColumns 与 BuildTitlePaths 相同，同时返回每一列的列名、编码后的title path以及每一级标题的来源
+func BuildTitleColumns(sheet Sheet, depth int, opts ...Option) (TitleColumns, error) {
+	paths, sources, err := buildTitleColumns(sheet, depth, opts...)
+	if err != nil {
+		return nil, err
+	}
+	tcs := make(TitleColumns, len(paths))
+	for i, tp := range paths {
+		tcs[i] = TitleColumn{
+			Index:   i,
+			Letter:  ColumnLetter(i),
+			Path:    tp,
+			Encoded: tp.Encode(),
+			Sources: sources[i],
+		}
+	}
+	return tcs, nil
+}
+
+func buildTitleColumns(sheet Sheet, depth int, opts ...Option) (TitlePaths, [][]SegmentSource, error) {
 	params := NewParams(opts...)
 
 	if depth < 1 {
-		return nil, errors.New("eorm: depth must be greater than 0")
+		return nil, nil, errors.New("eorm: depth must be greater than 0")
 	}
 	minRows := params.MinRows(depth)
 	if sheet == nil || sheet.RowCount() < minRows {
-		return nil, errors.New("eorm: sheet row count must be greater than depth")
+		return nil, nil, errors.New("eorm: sheet row count must be greater than depth")
 	}
 
 	var columns TitlePaths
-	appendCell := func(idx int, val string, emptyAsMerged bool) {
+	var sources [][]SegmentSource
+	appendCell := func(idx int, val string, source SegmentSource, emptyAsMerged bool) {
 		for idx >= len(columns) {
 			// 当前row列数多于之前列数
 			if len(columns) > 0 {
 				path := columns[len(columns)-1].Truncate(1)
 				columns = append(columns, path)
+				// 新增列的上级标题来自左侧单元格，通配符仍然是通配符
+				srcs := make([]SegmentSource, len(path))
+				for k := range srcs {
+					srcs[k] = SourceMerged
+					if sources[len(sources)-1][k] == SourceWildcard {
+						srcs[k] = SourceWildcard
+					}
+				}
+				sources = append(sources, srcs)
 			} else {
 				columns = append(columns, TitlePath(nil))
+				sources = append(sources, nil)
 			}
 		}
 		if val == "" && idx > 0 && emptyAsMerged {
 			// 空被认为是合并单元格
 			val = columns[idx-1].Last()
+			source = SourceMerged
 		}
 		columns[idx] = append(columns[idx], val)
+		sources[idx] = append(sources[idx], source)
 	}
 
 	for i := params.TitleStartRow; i < minRows; i++ {
 		emptyAsMerged := i != minRows-1 || !params.GenLastRowNoMerged
 		row, err := sheet.GetRow(i)
 		if err != nil {
-			return nil, fmt.Errorf("eorm: get row %d: %w", i, err)
+			return nil, nil, fmt.Errorf("eorm: get row %d: %w", i, err)
 		}
 		colCount := row.ColumnCount()
 		j := 0
 		for ; j < colCount; j++ {
 			var val string
+			source := SourceWildcard
 			if i != 0 || !params.GenWildcardForFirstRow {
+				source = SourceCell
 				val, err = row.GetColumn(j)
 				if err != nil && !errors.Is(err, ErrEmptyCell) {
-					return nil, fmt.Errorf("eorm: get column %d: %w", j, err)
+					return nil, nil, fmt.Errorf("eorm: get column %d: %w", j, err)
 				}
 				if params.TrimSpace {
 					val = strings.TrimSpace(val)
 				}
 			}
-			appendCell(j, val, emptyAsMerged)
+			appendCell(j, val, source, emptyAsMerged && source != SourceWildcard)
 		}
 		// 当前row列数少于之前列数
 		for ; j < len(columns); j++ {
-			appendCell(j, "", emptyAsMerged)
+			source := SourceCell
+			if i == 0 && params.GenWildcardForFirstRow {
+				source = SourceWildcard
+			}
+			appendCell(j, "", source, emptyAsMerged && source != SourceWildcard)
 		}
 	}
-	return columns, nil
+	return columns, sources, nil
+}
+
+// ColumnLetter 列下标转换为Excel的列名，0 -> A, 26 -> AA
+func ColumnLetter(index int) string {
+	var name []byte
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = append([]byte{byte('A' + (index-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// Paths 返回所有列的title path
+func (tcs TitleColumns) Paths() TitlePaths {
+	tps := make(TitlePaths, len(tcs))
+	for i, tc := range tcs {
+		tps[i] = tc.Path
+	}
+	return tps
 }
 
 func (tps TitlePaths) Info() string {
@@ -76,3 +160,90 @@ func (tps TitlePaths) Info() string {
 	}
 	return sb.String()
 }
+
+// SuggestTitleLayout 根据sheet的前scanRows行推测表头的起始行(TitleStartRow)和深度，结果仅供参考：
+//   - 跳过开头的空行以及只有一个非空单元格的标题行
+//   - 存在横向合并(或者空单元格下方的行在该列有值)的行被认为是上级表头，直到数值为主的数据行
+//
+// sheet实现了 MergedSheet 时使用其合并单元格，否则只根据单元格的值推测
+func SuggestTitleLayout(sheet Sheet, scanRows int) (startRow, depth int) {
+	var rows [][]string
+	width := 0
+	for i := 0; i < sheet.RowCount() && i < scanRows; i++ {
+		var cols []string
+		if row, err := sheet.GetRow(i); err == nil && row != nil {
+			for j, v := range row.AllColumns() {
+				for len(cols) <= j {
+					cols = append(cols, "")
+				}
+				cols[j] = strings.TrimSpace(v)
+			}
+		}
+		for len(cols) > 0 && cols[len(cols)-1] == "" {
+			cols = cols[:len(cols)-1]
+		}
+		width = max(width, len(cols))
+		rows = append(rows, cols)
+	}
+	merged, _ := GetMergedRanges(sheet)
+
+	nonEmpty := func(r int) (n, numeric int) {
+		for _, v := range rows[r] {
+			if v == "" {
+				continue
+			}
+			n++
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				numeric++
+			}
+		}
+		return n, numeric
+	}
+	cell := func(r, c int) string {
+		if c < len(rows[r]) {
+			return rows[r][c]
+		}
+		return ""
+	}
+	isTitle := func(r int) bool {
+		n, numeric := nonEmpty(r)
+		return n > 0 && numeric*2 < n
+	}
+	// isGroup 第r行是否为上级表头
+	isGroup := func(r int) bool {
+		if r+1 >= len(rows) || !isTitle(r+1) {
+			return false
+		}
+		for _, m := range merged {
+			if (m.FirstRow == r && m.LastColumn > m.FirstColumn) || (m.FirstRow <= r && m.LastRow > r) {
+				return true
+			}
+		}
+		// 左侧有值的空单元格被认为是横向合并
+		seen := false
+		for c := 0; c < width; c++ {
+			if cell(r, c) != "" {
+				seen = true
+			} else if seen && cell(r+1, c) != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	startRow = -1
+	for r := range rows {
+		if n, _ := nonEmpty(r); n > 1 || (n == 1 && width <= 1) {
+			startRow = r
+			break
+		}
+	}
+	if startRow < 0 {
+		return 0, 1
+	}
+	depth = 1
+	for r := startRow; isGroup(r); r++ {
+		depth++
+	}
+	return startRow, depth
+}
@@ -0,0 +1,205 @@
+package eorm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type updateObj struct {
+	Name   string `eorm:"名称"`
+	Score  int64  `eorm:"分数"`
+	Status string `eorm:"状态"`
+}
+
+// setterUpdateObj 通过Set方法赋值的属性：Date 有对应的 Get 方法，Status 没有
+type setterUpdateObj struct {
+	Name   string    `eorm:"名称"`
+	Date   time.Time `eorm:"日期"`
+	Status string    `eorm:"状态"`
+}
+
+func (o *setterUpdateObj) SetDate(s string)   { o.Date, _ = time.Parse(time.DateOnly, s) }
+func (o *setterUpdateObj) GetDate() string    { return o.Date.Format(time.DateOnly) }
+func (o *setterUpdateObj) SetStatus(s string) { o.Status = strings.ToUpper(s) }
+
+func TestUpdate(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := "Sheet1"
+	rows := [][]any{
+		{"名称", "备注", "分数", "状态"},
+		{"张三", "保留", 90},
+		{"李四", "", 50},
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "FF0000"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.SetCellStyle(sheet, "D2", "D3", style); err != nil {
+		t.Fatal(err)
+	}
+	src := new(bytes.Buffer)
+	if err = f.Write(src); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	wb, err := NewWorkbookByReadSeeker("scores.xlsx", bytes.NewReader(src.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	s, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := Open[updateObj](s, WithTrackRows(), WithIgnoreOutOfRange())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for obj, err := range e.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj.Status = "通过"
+		if obj.Score < 60 {
+			obj.Status = "补考"
+		}
+		if err = e.UpdateObject(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = e.UpdateObject(&updateObj{}); !errors.Is(err, ErrRowNotFound) {
+		t.Fatalf("untracked object: %v", err)
+	}
+	if got, err := e.Get(2); err != nil || got.Status != "补考" {
+		t.Fatalf("updated row should be read back: %+v %v", got, err)
+	}
+	if err = e.Update(0, &updateObj{}); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("header row: %v", err)
+	}
+	dst := new(bytes.Buffer)
+	if err = e.SaveTo(dst); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = excelize.OpenReader(bytes.NewReader(dst.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	want := map[string]string{"B2": "保留", "C2": "90", "D2": "通过", "D3": "补考"}
+	for cell, v := range want {
+		if got, _ := f.GetCellValue(sheet, cell); got != v {
+			t.Fatalf("%s: %q, want %q", cell, got, v)
+		}
+	}
+	if got, _ := f.GetCellStyle(sheet, "D3"); got != style {
+		t.Fatalf("style of D3 changed: %d, want %d", got, style)
+	}
+
+	csvWb, err := NewWorkbookByReadSeeker("scores.csv", bytes.NewReader([]byte("名称,分数,状态\n张三,90,\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := csvWb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ce, err := Open[updateObj](cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ce.Update(1, &updateObj{}); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("csv should not be editable: %v", err)
+	}
+}
+
+func TestUpdateSetter(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := "Sheet1"
+	rows := [][]any{
+		{"名称", "日期", "状态"},
+		{"张三", "2024-01-02", "pass"},
+		{"李四", "2024-02-03", "fail"},
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := new(bytes.Buffer)
+	if err := f.Write(src); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	wb, err := NewWorkbookByReadSeeker("setter.xlsx", bytes.NewReader(src.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = wb.Close()
+	}()
+	s, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := Open[setterUpdateObj](s, WithTrackRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// DecodeAll 转换的对象同样被记录
+	results, err := e.DecodeAll(context.Background(), 2)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expecting 2 rows, got %d %v", len(results), err)
+	}
+	for _, res := range results {
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+		if rowIndex, ok := e.RowIndexOf(res.Obj); !ok || rowIndex != res.RowIndex {
+			t.Fatalf("row %d not tracked: %d %t", res.RowIndex, rowIndex, ok)
+		}
+		res.Obj.Date = res.Obj.Date.AddDate(0, 0, 1)
+		res.Obj.Status += "!"
+		if err = e.UpdateObject(res.Obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dst := new(bytes.Buffer)
+	if err = e.SaveTo(dst); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = excelize.OpenReader(bytes.NewReader(dst.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	// 日期通过 GetDate 写回，状态没有 Get 方法，单元格不变(而不是写入转换后的 PASS!)
+	want := map[string]string{"B2": "2024-01-03", "B3": "2024-02-04", "C2": "pass", "C3": "fail"}
+	for cell, v := range want {
+		if got, _ := f.GetCellValue(sheet, cell); got != v {
+			t.Fatalf("%s: %q, want %q", cell, got, v)
+		}
+	}
+}
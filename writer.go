@@ -0,0 +1,152 @@
+package eorm
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type (
+	// WorkbookWriter 生成只有一个sheet的workbook，行列下标从0开始。
+	// value 可以为 string、整数、浮点数、bool、time.Time 或 nil(不写入)，其他类型按 fmt.Sprint 写入
+	WorkbookWriter interface {
+		SetCell(rowIndex, columnIndex int, value any) error
+		MergeCells(area CellRange) error
+		SaveTo(w io.Writer) error
+		Close() error
+	}
+
+	xlsxWriter struct {
+		f     *excelize.File
+		sheet string
+	}
+)
+
+// NewWorkbookWriter 创建format格式的 WorkbookWriter，目前支持 FormatXlsx 和 FormatXls(BIFF8)，
+// sheetName 为空时为"Sheet1"
+func NewWorkbookWriter(format FileFormat, sheetName string) (WorkbookWriter, error) {
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+	switch format {
+	case FormatXlsx:
+		f := excelize.NewFile()
+		if err := f.SetSheetName(f.GetSheetName(0), sheetName); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("excel/xlsx: set sheet name: %w", err)
+		}
+		return &xlsxWriter{f: f, sheet: sheetName}, nil
+	case FormatXls:
+		return newXlsWriter(sheetName)
+	default:
+		return nil, fmt.Errorf("eorm: write %s: %w", format, ErrUnsupportedFormat)
+	}
+}
+
+func (x *xlsxWriter) SetCell(rowIndex, columnIndex int, value any) error {
+	if value == nil {
+		return nil
+	}
+	cell, err := excelize.CoordinatesToCellName(columnIndex+1, rowIndex+1)
+	if err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	if err = x.f.SetCellValue(x.sheet, cell, value); err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	return nil
+}
+
+func (x *xlsxWriter) MergeCells(area CellRange) error {
+	first, err := excelize.CoordinatesToCellName(area.FirstColumn+1, area.FirstRow+1)
+	if err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	last, err := excelize.CoordinatesToCellName(area.LastColumn+1, area.LastRow+1)
+	if err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	if err = x.f.MergeCell(x.sheet, first, last); err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	return nil
+}
+
+func (x *xlsxWriter) SaveTo(w io.Writer) error {
+	if err := x.f.Write(w); err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	return nil
+}
+
+func (x *xlsxWriter) Close() error {
+	return x.f.Close()
+}
+
+// Export 将objs按T的eorm标签导出为format格式(见 NewWorkbookWriter)的workbook并写入w。
+// 表头与 GenerateTemplate 相同，从第一行开始；slice属性按objs中最长的slice占用多列，每列的title path相同。
+// 通过Set方法赋值的属性与 EORM.Update 一样使用 Get<Field>() 方法的返回值，没有该方法时单元格为空
+func Export[T any](w io.Writer, format FileFormat, sheetName string, objs []T) error {
+	schema, err := CompileMapperSchema(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+	if len(schema.fields) == 0 {
+		return fmt.Errorf("eorm: no eorm tag found in %s", schema.typ)
+	}
+	columns := exportColumns(schema, objs)
+	ww, err := NewWorkbookWriter(format, sheetName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = ww.Close()
+	}()
+
+	depth := schema.tree.Depth()
+	for _, hc := range headerLayout(columns, depth) {
+		if err = ww.SetCell(hc.area.FirstRow, hc.area.FirstColumn, hc.title); err != nil {
+			return err
+		}
+		if hc.area.LastRow > hc.area.FirstRow || hc.area.LastColumn > hc.area.FirstColumn {
+			if err = ww.MergeCells(hc.area); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range objs {
+		ptr := reflect.ValueOf(&objs[i])
+		for columnIndex, c := range columns {
+			cm := schema.fields[c.field]
+			var v any
+			if fv, ok := cm.cellSource(ptr); ok {
+				v = fieldCellValue(fv, cm.mappingType.IsSlice(), c.element)
+			}
+			if err = ww.SetCell(depth+i, columnIndex, v); err != nil {
+				return err
+			}
+		}
+	}
+	return ww.SaveTo(w)
+}
+
+// exportColumns 在 templateColumns 的基础上，slice属性按objs中最长的slice(至少一个元素)展开为多列
+func exportColumns[T any](s *MapperSchema, objs []T) []*templateColumn {
+	var columns []*templateColumn
+	for _, c := range s.templateColumns() {
+		count := 1
+		if s.fields[c.field].mappingType.IsSlice() {
+			for i := range objs {
+				if fv, ok := s.fields[c.field].cellSource(reflect.ValueOf(&objs[i])); ok && fv.Kind() == reflect.Slice {
+					count = max(count, fv.Len())
+				}
+			}
+		}
+		for k := 0; k < count; k++ {
+			columns = append(columns, &templateColumn{field: c.field, element: k, path: c.path})
+		}
+	}
+	return columns
+}
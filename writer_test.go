@@ -0,0 +1,175 @@
+package eorm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/richardlehane/mscfb"
+)
+
+type exportObj struct {
+	ID     int64    `eorm:"编号//"`
+	Name   string   `eorm:"基本信息/姓名/"`
+	Score  float64  `eorm:"基本信息/成绩/分数"`
+	Passed bool     `eorm:"基本信息/成绩/通过"`
+	Tags   []string `eorm:"标签//"`
+}
+
+func TestExport(t *testing.T) {
+	objs := []exportObj{
+		{ID: 1, Name: "张三", Score: 92.5, Passed: true, Tags: []string{"a", "b"}},
+		{ID: 2, Name: "李四", Score: 58, Tags: []string{"c"}},
+		{ID: 3, Name: strings.Repeat("长", 5000), Score: 60, Passed: true},
+	}
+	for i := 4; i <= 3000; i++ {
+		objs = append(objs, exportObj{ID: int64(i), Name: fmt.Sprintf("name-%d", i), Score: float64(i % 100)})
+	}
+	for _, format := range []FileFormat{FormatXls, FormatXlsx} {
+		t.Run(format.String(), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := Export(buf, format, "成绩", objs); err != nil {
+				t.Fatal(err)
+			}
+			if detected, err := DetectFormat(bytes.NewReader(buf.Bytes())); err != nil || detected != format {
+				t.Fatalf("detected %s %v", detected, err)
+			}
+			wb, err := NewWorkbookByReadSeeker("export."+format.String(), bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = wb.Close()
+			}()
+			sheet, err := wb.GetSheetByName("成绩")
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, err := Open[exportObj](sheet, WithIgnoreOutOfRange())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !e.IsPerfectMatch() {
+				t.Fatal("exported header should match the type perfectly")
+			}
+			var got []exportObj
+			for obj, err := range e.All() {
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, *obj)
+			}
+			if len(got) != len(objs) {
+				t.Fatalf("got %d rows, want %d", len(got), len(objs))
+			}
+			for i := range objs {
+				want := objs[i]
+				// 缺少的slice元素转换为空字符串
+				for len(want.Tags) < 2 {
+					want.Tags = append(want.Tags, "")
+				}
+				if !reflect.DeepEqual(got[i], want) {
+					t.Fatalf("row %d: got %+v, want %+v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestXlsMergedCells(t *testing.T) {
+	ww, err := NewWorkbookWriter(FormatXls, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ww.SetCell(0, 0, "title"); err != nil {
+		t.Fatal(err)
+	}
+	if err = ww.MergeCells(CellRange{FirstRow: 0, FirstColumn: 0, LastRow: 1, LastColumn: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err = ww.SetCell(XlsMaxRows, 0, 1); err == nil {
+		t.Fatal("row out of range should fail")
+	}
+	buf := new(bytes.Buffer)
+	if err = ww.SaveTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	stream := readCompoundStream(t, buf.Bytes(), "Workbook")
+	// MERGEDCELLS: 1个区域 A1:C2
+	want := append(le16(biffMergedCells, 10, 1), le16(0, 1, 0, 2)...)
+	if !bytes.Contains(stream, want) {
+		t.Fatal("MERGEDCELLS record not found")
+	}
+
+	// 超过一个 MERGEDCELLS 记录能容纳的区域数，读取时合并所有记录
+	ww, err = NewWorkbookWriter(FormatXls, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ranges []CellRange
+	for i := 0; i < xlsMaxMergedCells+10; i++ {
+		r := CellRange{FirstRow: 2 * i, FirstColumn: i % 5, LastRow: 2*i + 1, LastColumn: i%5 + 1}
+		if err = ww.MergeCells(r); err != nil {
+			t.Fatal(err)
+		}
+		ranges = append(ranges, r)
+	}
+	buf.Reset()
+	if err = ww.SaveTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	wb, err := NewXlsWorkbookByReadSeeker(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := wb.GetSheet(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetMergedRanges(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, ranges) {
+		t.Fatalf("expecting %d merged ranges, got %d", len(ranges), len(got))
+	}
+}
+
+func TestWriteCompoundFile(t *testing.T) {
+	// 超过109个FAT扇区时需要DIFAT
+	for _, size := range []int{10, 5000, 8 << 20} {
+		stream := make([]byte, size)
+		for i := range stream {
+			stream[i] = byte(i % 251)
+		}
+		buf := new(bytes.Buffer)
+		if err := writeCompoundFile(buf, "Workbook", stream); err != nil {
+			t.Fatal(err)
+		}
+		got := readCompoundStream(t, buf.Bytes(), "Workbook")
+		if !bytes.Equal(got[:size], stream) || len(got) < cfbMiniCutoff {
+			t.Fatalf("size %d: stream mismatch, got %d bytes", size, len(got))
+		}
+	}
+}
+
+func readCompoundStream(t *testing.T, data []byte, name string) []byte {
+	doc, err := mscfb.New(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name == name {
+			stream, err := io.ReadAll(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return stream
+		}
+	}
+	t.Fatalf("stream %s not found", name)
+	return nil
+}
@@ -1,12 +1,19 @@
 package eorm
 
 import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"iter"
+	"math"
+	"os"
 	"strconv"
 	"strings"
 
+	"github.com/richardlehane/mscfb"
 	"github.com/shakinm/xlsReader/xls"
 	"github.com/shakinm/xlsReader/xls/record"
 	"github.com/shakinm/xlsReader/xls/structure"
@@ -159,16 +166,25 @@ type (
 	xlsSheet struct {
 		rowCount int
 		sheet    *xls.Sheet
+		limiter  *limiter
+		merged   []CellRange
+		mergeErr error
 	}
 
 	xlsRowIterator struct {
-		curRow int
-		sheet  *xlsSheet
+		ctx      context.Context
+		curRow   int
+		sheet    *xlsSheet
+		canceled bool // ctx被取消后 Next 已经返回过一次true
 	}
 
+	// xlsWorkbook xlsReader 在打开时即解析整个文件，所以资源限制在获取sheet和行时检查
 	xlsWorkbook struct {
 		nameMap  map[string]int // name -> index
 		workbook xls.Workbook
+		limiter  *limiter
+		merged   [][]CellRange // 以sheet下标为下标，xlsReader 不读取合并单元格，由 readXlsMergedCells 读取
+		mergeErr error
 	}
 
 	xlsReaderRow interface {
@@ -222,35 +238,68 @@ func (x *xlsSheet) GetName() string {
 	return x.sheet.GetName()
 }
 
+// MergedRanges 返回sheet中 MERGEDCELLS 记录的所有区域
+func (x *xlsSheet) MergedRanges() ([]CellRange, error) {
+	if x.mergeErr != nil {
+		return nil, fmt.Errorf("excel/xls: merged cells: %w", x.mergeErr)
+	}
+	return x.merged, nil
+}
+
 func (x *xlsSheet) RowCount() int {
 	return x.rowCount
 }
 
 func (x *xlsSheet) GetRow(index int) (Row, error) {
+	return x.GetRowContext(context.Background(), index)
+}
+
+func (x *xlsSheet) GetRowContext(ctx context.Context, index int) (Row, error) {
 	if index < 0 || index >= x.rowCount {
 		return nil, ErrOutOfRange
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("excel/xls: %w", err)
+	}
+	if err := x.limiter.checkDeadline(); err != nil {
+		return nil, fmt.Errorf("excel/xls: %w", err)
+	}
 	row, err := x.sheet.GetRow(index)
 	if err != nil {
 		return nil, fmt.Errorf("excel/xls: %w", err)
 	}
 	cols := row.GetCols()
-	return &xlsRow{cols: cols}, nil
+	xr := &xlsRow{cols: cols}
+	if err = x.limiter.checkRow(xr); err != nil {
+		return nil, fmt.Errorf("excel/xls: row %d: %w", index, err)
+	}
+	return xr, nil
 }
 
+// Next ctx被取消后返回一次true，使 Current 返回 ctx.Err()，以区别于正常结束，之后返回false
 func (x *xlsRowIterator) Next() bool {
+	if x.ctx.Err() != nil {
+		if x.canceled {
+			return false
+		}
+		x.canceled = true
+		return true
+	}
 	x.curRow++
 	return x.curRow < x.sheet.rowCount
 }
 
 func (x *xlsRowIterator) Current() (Row, error) {
+	if err := x.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("excel/xls: %w", err)
+	}
 	if x.curRow < 0 {
 		return nil, ErrExcelNotInitialized
 	}
 	if x.curRow >= x.sheet.rowCount {
 		return nil, ErrEof
 	}
-	return x.sheet.GetRow(x.curRow)
+	return x.sheet.GetRowContext(x.ctx, x.curRow)
 }
 
 func (x *xlsRowIterator) Close() error { return nil }
@@ -268,54 +317,215 @@ func (x *xlsWorkbook) getSheet(index int) (*xlsSheet, error) {
 	if sheet != nil {
 		rowCount = sheet.GetNumberRows()
 	}
-	return &xlsSheet{sheet: sheet, rowCount: rowCount}, nil
+	if err = x.limiter.checkRows(rowCount); err != nil {
+		return nil, fmt.Errorf("excel/xls: %w", err)
+	}
+	xs := &xlsSheet{sheet: sheet, rowCount: rowCount, limiter: x.limiter, mergeErr: x.mergeErr}
+	if index < len(x.merged) {
+		xs.merged = x.merged[index]
+	}
+	return xs, nil
 }
 
 func (x *xlsWorkbook) GetSheetByName(name string) (Sheet, error) {
+	return x.GetSheetByNameContext(context.Background(), name)
+}
+
+func (x *xlsWorkbook) GetSheet(index int) (Sheet, error) {
+	return x.GetSheetContext(context.Background(), index)
+}
+
+func (x *xlsWorkbook) IterateSheet(index int) (RowIterator, error) {
+	return x.IterateSheetContext(context.Background(), index)
+}
+
+// GetSheetByNameContext xlsReader 在打开文件时已解析全部内容，所以ctx只在获取sheet及读取行时检查
+func (x *xlsWorkbook) GetSheetByNameContext(ctx context.Context, name string) (Sheet, error) {
 	idx, exist := x.nameMap[name]
 	if !exist {
 		return nil, ErrNotFound
 	}
-	return x.getSheet(idx)
+	return x.GetSheetContext(ctx, idx)
 }
 
-func (x *xlsWorkbook) GetSheet(index int) (Sheet, error) {
+func (x *xlsWorkbook) GetSheetContext(ctx context.Context, index int) (Sheet, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("excel/xls: %w", err)
+	}
 	return x.getSheet(index)
 }
 
-func (x *xlsWorkbook) IterateSheet(index int) (RowIterator, error) {
+func (x *xlsWorkbook) IterateSheetContext(ctx context.Context, index int) (RowIterator, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("excel/xls: %w", err)
+	}
 	sheet, err := x.getSheet(index)
 	if err != nil {
 		return nil, err
 	}
-	return &xlsRowIterator{curRow: -1, sheet: sheet}, nil
+	return &xlsRowIterator{ctx: ctx, curRow: -1, sheet: sheet}, nil
 }
 
 func (x *xlsWorkbook) Close() error {
 	return nil
 }
 
-func newWorkbook(wb xls.Workbook) *xlsWorkbook {
+func newWorkbook(wb xls.Workbook, lm *limiter) (*xlsWorkbook, error) {
+	if err := lm.checkDeadline(); err != nil {
+		return nil, fmt.Errorf("excel/xls: %w", err)
+	}
 	nameMap := make(map[string]int)
 	sheets := wb.GetSheets()
 	for i, sheet := range sheets {
 		nameMap[sheet.GetName()] = i
 	}
-	return &xlsWorkbook{workbook: wb, nameMap: nameMap}
+	return &xlsWorkbook{workbook: wb, nameMap: nameMap, limiter: lm}, nil
 }
 
-func NewXlsWorkbook(filePath string) (Workbook, error) {
-	workbook, err := xls.OpenFile(filePath)
+func NewXlsWorkbook(filePath string, opts ...WorkbookOption) (Workbook, error) {
+	f, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("excel/xls: %w", err)
 	}
-	return newWorkbook(workbook), nil
+	defer func() {
+		_ = f.Close()
+	}()
+	return NewXlsWorkbookByReadSeeker(f, opts...)
 }
 
-func NewXlsWorkbookByReadSeeker(reader io.ReadSeeker) (Workbook, error) {
+// NewXlsWorkbookByReadSeeker xlsReader 在打开时读取全部内容，返回后不再使用reader
+func NewXlsWorkbookByReadSeeker(reader io.ReadSeeker, opts ...WorkbookOption) (Workbook, error) {
+	lm := NewWorkbookParams(opts...).startLimits()
+	ra, _, err := toReaderAt(reader)
+	if err != nil {
+		return nil, fmt.Errorf("excel/xls: %w", err)
+	}
+	// reader不支持 io.ReaderAt 时toReaderAt已经读取了全部内容，打开时不再重复读取
+	if rs, ok := ra.(io.ReadSeeker); ok {
+		reader = rs
+	}
+	// 合并单元格不影响读取数据，读取失败时由 MergedRanges 返回错误
+	merged, mergeErr := readXlsMergedCells(ra, lm)
+	if _, err = reader.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("excel/xls: %w", err)
+	}
 	wb, err := xls.OpenReader(reader)
 	if err != nil {
 		return nil, fmt.Errorf("excel/xls: %w", err)
 	}
-	return newWorkbook(wb), nil
+	x, err := newWorkbook(wb, lm)
+	if err != nil {
+		return nil, err
+	}
+	x.merged, x.mergeErr = merged, mergeErr
+	return x, nil
+}
+
+var errXlsTruncated = errors.New("truncated record")
+
+// readXlsMergedCells 由全局子流的 BOUNDSHEET 记录找到每个sheet子流，读取其中的 MERGEDCELLS 记录，
+// 返回值的下标与 BOUNDSHEET 的顺序(即sheet的下标)一致。以流的方式逐条读取记录，不缓存整个Workbook流
+func readXlsMergedCells(ra io.ReaderAt, lm *limiter) ([][]CellRange, error) {
+	doc, err := mscfb.New(ra)
+	if err != nil {
+		return nil, err
+	}
+	var stream *mscfb.File
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name == "Workbook" || entry.Name == "Book" {
+			stream = entry
+			break
+		}
+	}
+	if stream == nil {
+		return nil, ErrNotFound
+	}
+
+	var positions []int64
+	err = walkBiffRecords(stream, 0, func(typ uint16, data []byte) error {
+		if typ == biffBoundSheet {
+			if len(data) < 4 {
+				return errXlsTruncated
+			}
+			positions = append(positions, int64(binary.LittleEndian.Uint32(data)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	merged := make([][]CellRange, len(positions))
+	for i, pos := range positions {
+		if err = lm.checkDeadline(); err != nil {
+			return nil, err
+		}
+		err = walkBiffRecords(stream, pos, func(typ uint16, data []byte) error {
+			if typ != biffMergedCells {
+				return nil
+			}
+			if len(data) < 2 {
+				return errXlsTruncated
+			}
+			n := int(binary.LittleEndian.Uint16(data))
+			if len(data) < 2+8*n {
+				return errXlsTruncated
+			}
+			for k := 0; k < n; k++ {
+				ref := data[2+8*k:]
+				merged[i] = append(merged[i], CellRange{
+					FirstRow:    int(binary.LittleEndian.Uint16(ref)),
+					LastRow:     int(binary.LittleEndian.Uint16(ref[2:])),
+					FirstColumn: int(binary.LittleEndian.Uint16(ref[4:])),
+					LastColumn:  int(binary.LittleEndian.Uint16(ref[6:])),
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("sheet %d: %w", i, err)
+		}
+	}
+	return merged, nil
+}
+
+// walkBiffRecords 从stream中pos处的BOF开始遍历一个子流的记录直到对应的EOF，嵌入的子流(如图表)中的记录被跳过
+func walkBiffRecords(stream io.ReadSeeker, pos int64, fn func(typ uint16, data []byte) error) error {
+	if _, err := stream.Seek(pos, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(stream)
+	header := make([]byte, 4)
+	buf := make([]byte, math.MaxUint16)
+	depth := 0
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return errXlsTruncated
+			}
+			return err
+		}
+		typ := binary.LittleEndian.Uint16(header)
+		size := int(binary.LittleEndian.Uint16(header[2:]))
+		switch typ {
+		case biffBOF:
+			depth++
+		case biffEOF:
+			if depth--; depth <= 0 {
+				return nil
+			}
+		}
+		if depth != 1 || typ == biffBOF || typ == biffEOF {
+			if _, err := r.Discard(size); err != nil {
+				return errXlsTruncated
+			}
+			continue
+		}
+		data := buf[:size]
+		if _, err := io.ReadFull(r, data); err != nil {
+			return errXlsTruncated
+		}
+		if err := fn(typ, data); err != nil {
+			return err
+		}
+	}
 }
@@ -1,9 +1,15 @@
 package eorm
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"iter"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -12,38 +18,131 @@ import (
 
 type (
 	xlsxWorkbook struct {
-		names []string
-		f     *excelize.File
+		names   []string
+		f       *excelize.File
+		limiter *limiter
 	}
 
 	xlsxSheet struct {
 		name    string
 		allRows [][]string
+		f       *excelize.File
 	}
 
 	xlsxRowIterator struct {
-		rows *excelize.Rows
+		ctx      context.Context
+		rows     *excelize.Rows
+		limiter  *limiter
+		count    int  // 已遍历的行数
+		canceled bool // ctx被取消后 Next 已经返回过一次true
 	}
 
 	xlsxRow []string
 )
 
-func NewXlsxWorkbook(filePath string) (Workbook, error) {
-	f, err := excelize.OpenFile(filePath)
+// NewXlsxWorkbook 打开xlsx文件，加密的文件需要通过 WithPassword 提供密码，
+// 未提供时返回 ErrPasswordRequired，密码错误时返回 ErrIncorrectPassword
+func NewXlsxWorkbook(filePath string, opts ...WorkbookOption) (Workbook, error) {
+	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("excel/xlsx: %w", err)
 	}
-	names := f.GetSheetList()
-	return &xlsxWorkbook{names: names, f: f}, nil
+	defer func() {
+		_ = file.Close()
+	}()
+	wb, err := newXlsxWorkbook(file, NewWorkbookParams(opts...))
+	if err != nil {
+		return nil, err
+	}
+	wb.f.Path = filePath
+	return wb, nil
 }
 
-func NewXlsxWorkbookByReadSeeker(reader io.ReadSeeker) (Workbook, error) {
-	f, err := excelize.OpenReader(reader)
+func NewXlsxWorkbookByReadSeeker(reader io.ReadSeeker, opts ...WorkbookOption) (Workbook, error) {
+	return newXlsxWorkbook(reader, NewWorkbookParams(opts...))
+}
+
+// newXlsxWorkbook 调用者已经确定了xlsx格式，只有提供了密码时才需要识别文件是否加密，
+// 此时读取的内容同时用于识别和解密，不会重复读取
+func newXlsxWorkbook(reader io.ReadSeeker, params *WorkbookParams) (*xlsxWorkbook, error) {
+	if params.Password == "" {
+		return openXlsxWorkbook(reader, params)
+	}
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
+	pkg, err := xlsxPackage(raw, params)
 	if err != nil {
+		return nil, err
+	}
+	return openXlsxWorkbook(pkg, params)
+}
+
+// openXlsxWorkbook 打开未加密的xlsx。打开失败且没有提供密码时，如果文件是加密的xlsx则返回 ErrPasswordRequired
+func openXlsxWorkbook(pkg io.ReadSeeker, params *WorkbookParams) (*xlsxWorkbook, error) {
+	lm := params.startLimits()
+	if params.Limits.MaxUnzipSize > 0 {
+		ra, size, err := toReaderAt(pkg)
+		if err != nil {
+			return nil, fmt.Errorf("excel/xlsx: %w", err)
+		}
+		// 不是zip时由excelize报告错误
+		if err = lm.checkZip(ra, size); errors.Is(err, ErrUnzipSizeExceeded) {
+			return nil, fmt.Errorf("excel/xlsx: %w", err)
+		}
+		// pkg不支持 io.ReaderAt 时toReaderAt已经读取了全部内容，不再重复读取
+		if rs, ok := ra.(io.ReadSeeker); ok {
+			pkg = rs
+		}
+	}
+	if err := lm.checkDeadline(); err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
+	f, err := excelize.OpenReader(pkg, params.xlsxOptions())
+	if err != nil {
+		if params.Password == "" {
+			if format, derr := DetectFormat(pkg); format == FormatXlsx && errors.Is(derr, ErrEncryptedFile) {
+				return nil, fmt.Errorf("excel/xlsx: %w", ErrPasswordRequired)
+			}
+		}
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
+	if err = lm.checkDeadline(); err != nil {
+		_ = f.Close()
 		return nil, fmt.Errorf("excel/xlsx: %w", err)
 	}
 	names := f.GetSheetList()
-	return &xlsxWorkbook{names: names, f: f}, nil
+	return &xlsxWorkbook{names: names, f: f, limiter: lm}, nil
+}
+
+// xlsxOptions Password 用于保存时重新加密；解压大小的限制(Limits.MaxUnzipSize)在打开之前检查，所以不传递给excelize
+func (p *WorkbookParams) xlsxOptions() excelize.Options {
+	return excelize.Options{
+		Password:          p.Password,
+		UnzipSizeLimit:    p.UnzipSizeLimit,
+		UnzipXMLSizeLimit: p.UnzipXMLSizeLimit,
+	}
+}
+
+// xlsxPackage 返回xlsx的zip内容，加密的文件在这里解密，使解密后的内容同样可以在打开之前检查 Limits.MaxUnzipSize。
+// 只有文件确实是加密的xlsx时，才会返回 ErrIncorrectPassword。
+// 解密时不校验密码，密码错误时解密后的内容不是zip；而解密失败说明加密文件本身已损坏
+func xlsxPackage(raw []byte, params *WorkbookParams) (io.ReadSeeker, error) {
+	if format, err := DetectFormat(bytes.NewReader(raw)); format != FormatXlsx || !errors.Is(err, ErrEncryptedFile) {
+		return bytes.NewReader(raw), nil
+	}
+	pkg, err := excelize.Decrypt(raw, &excelize.Options{Password: params.Password})
+	if err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w: %w", ErrEncryptedFile, err)
+	}
+	if _, err = zip.NewReader(bytes.NewReader(pkg), int64(len(pkg))); err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", ErrIncorrectPassword)
+	}
+	return bytes.NewReader(pkg), nil
 }
 
 func (x *xlsxWorkbook) SheetCount() int {
@@ -51,29 +150,90 @@ func (x *xlsxWorkbook) SheetCount() int {
 }
 
 func (x *xlsxWorkbook) GetSheet(index int) (Sheet, error) {
+	return x.GetSheetContext(context.Background(), index)
+}
+
+func (x *xlsxWorkbook) GetSheetByName(name string) (Sheet, error) {
+	return x.GetSheetByNameContext(context.Background(), name)
+}
+
+func (x *xlsxWorkbook) GetSheetContext(ctx context.Context, index int) (Sheet, error) {
 	if index < 0 || index >= len(x.names) {
 		return nil, ErrOutOfRange
 	}
-	return x.GetSheetByName(x.names[index])
+	return x.GetSheetByNameContext(ctx, x.names[index])
 }
 
-func (x *xlsxWorkbook) GetSheetByName(name string) (Sheet, error) {
-	rows, err := x.f.GetRows(name)
+// GetSheetByNameContext 以流的方式读取sheet的所有行，每读取一行检查一次ctx
+func (x *xlsxWorkbook) GetSheetByNameContext(ctx context.Context, name string) (Sheet, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
+	if !slices.Contains(x.names, name) {
+		return nil, ErrNotFound
+	}
+	rows, err := x.f.Rows(name)
 	if err != nil {
 		return nil, fmt.Errorf("excel/xlsx: %w", err)
 	}
-	return &xlsxSheet{name: name, allRows: rows}, nil
+	allRows, err := readXlsxRows(ctx, rows, x.limiter)
+	if cerr := rows.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
+	return &xlsxSheet{name: name, allRows: allRows, f: x.f}, nil
+}
+
+// readXlsxRows 与 excelize.File.GetRows 一致，填充中间的空行并去掉末尾的空行，同时在读取过程中检查资源限制
+func readXlsxRows(ctx context.Context, rows *excelize.Rows, lm *limiter) ([][]string, error) {
+	results, cur, maxVal := make([][]string, 0, 64), 0, 0
+	for rows.Next() {
+		cur++
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := lm.checkDeadline(); err != nil {
+			return nil, err
+		}
+		row, err := rows.Columns()
+		if err != nil {
+			break
+		}
+		if len(row) > 0 {
+			if err = lm.checkRows(cur); err != nil {
+				return nil, err
+			}
+			if err = lm.checkStrings(row); err != nil {
+				return nil, fmt.Errorf("row %d: %w", cur-1, err)
+			}
+			if emptyRows := cur - maxVal - 1; emptyRows > 0 {
+				results = append(results, make([][]string, emptyRows)...)
+			}
+			results = append(results, row)
+			maxVal = cur
+		}
+	}
+	return results[:maxVal], nil
 }
 
 func (x *xlsxWorkbook) IterateSheet(index int) (RowIterator, error) {
+	return x.IterateSheetContext(context.Background(), index)
+}
+
+func (x *xlsxWorkbook) IterateSheetContext(ctx context.Context, index int) (RowIterator, error) {
 	if index < 0 || index >= len(x.names) {
 		return nil, ErrOutOfRange
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
 	rows, err := x.f.Rows(x.names[index])
 	if err != nil {
 		return nil, fmt.Errorf("excel/xlsx: %w", err)
 	}
-	return &xlsxRowIterator{rows: rows}, nil
+	return &xlsxRowIterator{ctx: ctx, rows: rows, limiter: x.limiter}, nil
 }
 
 func (x *xlsxWorkbook) Close() error {
@@ -99,6 +259,63 @@ func (x xlsxSheet) GetRow(index int) (Row, error) {
 	return xlsxRow(x.allRows[index]), nil
 }
 
+// MergedRanges 读取sheet的合并单元格，workbook关闭后不能调用
+func (x xlsxSheet) MergedRanges() ([]CellRange, error) {
+	cells, err := x.f.GetMergeCells(x.name)
+	if err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
+	ranges := make([]CellRange, 0, len(cells))
+	for _, cell := range cells {
+		c1, r1, err := excelize.CellNameToCoordinates(cell.GetStartAxis())
+		if err != nil {
+			return nil, fmt.Errorf("excel/xlsx: %w", err)
+		}
+		c2, r2, err := excelize.CellNameToCoordinates(cell.GetEndAxis())
+		if err != nil {
+			return nil, fmt.Errorf("excel/xlsx: %w", err)
+		}
+		ranges = append(ranges, CellRange{FirstRow: r1 - 1, FirstColumn: c1 - 1, LastRow: r2 - 1, LastColumn: c2 - 1})
+	}
+	return ranges, nil
+}
+
+// SetCell 修改单元格的值并同步更新已读取的行，workbook关闭后不能调用
+func (x *xlsxSheet) SetCell(rowIndex, columnIndex int, value any) error {
+	if rowIndex < 0 || columnIndex < 0 {
+		return ErrOutOfRange
+	}
+	cell, err := excelize.CoordinatesToCellName(columnIndex+1, rowIndex+1)
+	if err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	if err = x.f.SetCellValue(x.name, cell, value); err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	val, err := x.f.GetCellValue(x.name, cell)
+	if err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	for len(x.allRows) <= rowIndex {
+		x.allRows = append(x.allRows, nil)
+	}
+	row := x.allRows[rowIndex]
+	for len(row) <= columnIndex {
+		row = append(row, "")
+	}
+	row[columnIndex] = val
+	x.allRows[rowIndex] = row
+	return nil
+}
+
+// SaveTo 将整个workbook(包括其他sheet)写入w，workbook关闭后不能调用
+func (x *xlsxSheet) SaveTo(w io.Writer) error {
+	if err := x.f.Write(w); err != nil {
+		return fmt.Errorf("excel/xlsx: %w", err)
+	}
+	return nil
+}
+
 func (x xlsxRow) ColumnCount() int {
 	return len(x)
 }
@@ -172,18 +389,44 @@ func (x xlsxRow) AllColumns() iter.Seq2[int, string] {
 	}
 }
 
-func (x xlsxRowIterator) Next() bool {
-	return x.rows.Next()
+// Next ctx被取消后返回一次true，使 Current 返回 ctx.Err()，以区别于正常结束，之后返回false
+func (x *xlsxRowIterator) Next() bool {
+	if x.ctx.Err() != nil {
+		if x.canceled {
+			return false
+		}
+		x.canceled = true
+		return true
+	}
+	if !x.rows.Next() {
+		return false
+	}
+	x.count++
+	return true
 }
 
-func (x xlsxRowIterator) Current() (Row, error) {
+func (x *xlsxRowIterator) Current() (Row, error) {
+	if err := x.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
+	if err := x.limiter.checkDeadline(); err != nil {
+		return nil, fmt.Errorf("excel/xlsx: %w", err)
+	}
 	row, err := x.rows.Columns()
 	if err != nil {
 		return nil, fmt.Errorf("excel/xlsx: %w", err)
 	}
+	if len(row) > 0 {
+		if err = x.limiter.checkRows(x.count); err != nil {
+			return nil, fmt.Errorf("excel/xlsx: %w", err)
+		}
+		if err = x.limiter.checkStrings(row); err != nil {
+			return nil, fmt.Errorf("excel/xlsx: %w", err)
+		}
+	}
 	return xlsxRow(row), nil
 }
 
-func (x xlsxRowIterator) Close() error {
+func (x *xlsxRowIterator) Close() error {
 	return x.rows.Close()
 }